@@ -0,0 +1,131 @@
+package basis
+
+import combinator "github.com/jiro4989/colc/combinator/v2"
+
+// Basis は組み込みのコンビネータ定義の組み合わせである。
+type Basis struct {
+	// Name は--basisで指定する名前である。
+	Name string
+	// Description はcolc basesで出力する説明である。
+	Description string
+	// Combinators はコンビネータ定義である。
+	Combinators []combinator.Combinator
+}
+
+var (
+	// SKI はS、K、Iの組み合わせである。
+	// コンビネータ定義ファイルを指定しない場合はこの組み合わせを使う。
+	SKI = Basis{
+		Name:        "ski",
+		Description: "S、K、I",
+		Combinators: []combinator.Combinator{
+			{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+			{Name: "K", ArgsCount: 2, Format: "{0}"},
+			{Name: "I", ArgsCount: 1, Format: "{0}"},
+		},
+	}
+	// SK はSとKだけの組み合わせである。
+	SK = Basis{
+		Name:        "sk",
+		Description: "SとKだけ。IはSKKで表す",
+		Combinators: []combinator.Combinator{
+			{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+			{Name: "K", ArgsCount: 2, Format: "{0}"},
+		},
+	}
+	// BCKW はB、C、K、Wの組み合わせである。
+	BCKW = Basis{
+		Name:        "bckw",
+		Description: "B、C、K、W。IはWKで表す",
+		Combinators: []combinator.Combinator{
+			{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+			{Name: "C", ArgsCount: 3, Format: "{0}{2}{1}"},
+			{Name: "K", ArgsCount: 2, Format: "{0}"},
+			{Name: "W", ArgsCount: 2, Format: "{0}{1}{1}"},
+		},
+	}
+	// Birds はSmullyanの「ものまね鳥をまねる」の鳥の名前の組み合わせである。
+	Birds = Basis{
+		Name:        "birds",
+		Description: "Smullyanの鳥の名前(Starling、Kestrel、Idiotなど)",
+		Combinators: []combinator.Combinator{
+			{Name: "Starling", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+			{Name: "Kestrel", ArgsCount: 2, Format: "{0}"},
+			{Name: "Idiot", ArgsCount: 1, Format: "{0}"},
+			{Name: "Bluebird", ArgsCount: 3, Format: "{0}({1}{2})"},
+			{Name: "Cardinal", ArgsCount: 3, Format: "{0}{2}{1}"},
+			{Name: "Warbler", ArgsCount: 2, Format: "{0}{1}{1}"},
+			{Name: "Thrush", ArgsCount: 2, Format: "{1}{0}"},
+			{Name: "Mockingbird", ArgsCount: 1, Format: "{0}{0}"},
+			{Name: "Lark", ArgsCount: 2, Format: "{0}({1}{1})"},
+			{Name: "Owl", ArgsCount: 2, Format: "{1}({0}{1})"},
+			{Name: "Robin", ArgsCount: 3, Format: "{1}{2}{0}"},
+			{Name: "Vireo", ArgsCount: 3, Format: "{2}{0}{1}"},
+			{Name: "Kite", ArgsCount: 2, Format: "{1}"},
+		},
+	}
+	// Arithmetic はチャーチ数の計算に使うコンビネータの組み合わせである。
+	// config/combinator.jsonと同じ定義である。
+	Arithmetic = Basis{
+		Name:        "arithmetic",
+		Description: "S、K、I、B、Cとチャーチ数の計算(<zero>、<suc>、<true>など)",
+		Combinators: []combinator.Combinator{
+			{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+			{Name: "K", ArgsCount: 2, Format: "{0}"},
+			{Name: "I", ArgsCount: 1, Format: "{0}"},
+			{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+			{Name: "C", ArgsCount: 3, Format: "{0}{2}{1}"},
+			{Name: "D", ArgsCount: 0, Format: "C(BC(B(CI)K))"},
+			{Name: "<0>", ArgsCount: 0, Format: "KI"},
+			{Name: "<1>", ArgsCount: 0, Format: "SB(KI)"},
+			{Name: "<zero>", ArgsCount: 0, Format: "KI"},
+			{Name: "<suc>", ArgsCount: 0, Format: "SB"},
+			{Name: "<one>", ArgsCount: 0, Format: "<suc><zero>"},
+			{Name: "<p1_1>", ArgsCount: 0, Format: "I"},
+			{Name: "<p2_1>", ArgsCount: 0, Format: "K"},
+			{Name: "<p2_2>", ArgsCount: 0, Format: "KI"},
+			{Name: "<p3_1>", ArgsCount: 0, Format: "S(KK)K"},
+			{Name: "<p3_2>", ArgsCount: 0, Format: "KK"},
+			{Name: "<p3_3>", ArgsCount: 0, Format: "K(KI)"},
+			{Name: "Q", ArgsCount: 2, Format: "D(SB({1}<zero>))({0}({1}<zero>)({1}<one>))"},
+			{Name: "R", ArgsCount: 3, Format: "{2}(Q{1})(D<zero>{0})<one>"},
+			{Name: "<true>", ArgsCount: 0, Format: "K"},
+			{Name: "<false>", ArgsCount: 0, Format: "SK"},
+		},
+	}
+)
+
+// Bases は組み込みのコンビネータ定義の組み合わせの一覧である。
+var Bases = []Basis{SKI, SK, BCKW, Birds, Arithmetic}
+
+// Lookup は名前に一致する組み込みのコンビネータ定義の組み合わせを返す。
+// 返すコンビネータ定義は呼び出し元で変更してもよい複製である。
+func Lookup(name string) (Basis, bool) {
+	for _, b := range Bases {
+		if b.Name == name {
+			b.Combinators = append([]combinator.Combinator(nil), b.Combinators...)
+			return b, true
+		}
+	}
+	return Basis{}, false
+}
+
+// Merge はコンビネータ定義にaddのコンビネータ定義を追加した複製を返す。
+// 同じ名前のコンビネータ定義はaddの定義で置き換える。
+func Merge(base, add []combinator.Combinator) []combinator.Combinator {
+	ret := append([]combinator.Combinator(nil), base...)
+	for _, c := range add {
+		replaced := false
+		for i := range ret {
+			if ret[i].Name == c.Name {
+				ret[i] = c
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
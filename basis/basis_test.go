@@ -0,0 +1,87 @@
+package basis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBases(t *testing.T) {
+	// 全てのコンビネータは引数を適用すると1回計算できる
+	for _, b := range Bases {
+		for _, c := range b.Combinators {
+			var args []combinator.Term
+			for i := 0; i < c.ArgsCount; i++ {
+				args = append(args, &combinator.Atom{Name: "x" + strconv.Itoa(i)})
+			}
+			in := combinator.Apply(&combinator.Atom{Name: c.Name}, args...)
+			_, ok := combinator.Step(in, b.Combinators)
+			assert.True(t, ok, b.Name, c.Name)
+		}
+		assert.Empty(t, combinator.PrefixWarnings(b.Combinators), b.Name)
+		assert.Empty(t, combinator.Validate(b.Combinators), b.Name)
+	}
+}
+
+func TestBasesReduce(t *testing.T) {
+	type TD struct {
+		basis  string
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{basis: "ski", clcode: "SKIx", expect: "x", desc: "SKI"},
+		TD{basis: "sk", clcode: "SKKx", expect: "x", desc: "SK"},
+		TD{basis: "bckw", clcode: "WKx", expect: "x", desc: "BCKW"},
+		TD{basis: "birds", clcode: "Cardinal Kestrel x y", expect: "y", desc: "鳥の名前"},
+		TD{basis: "arithmetic", clcode: "<one>fx", expect: "fx", desc: "チャーチ数"},
+	}
+	for _, td := range tds {
+		b, ok := Lookup(td.basis)
+		assert.True(t, ok, td.desc)
+		in, err := combinator.Parse(td.clcode, b.Combinators)
+		assert.NoError(t, err, td.desc)
+		actual := combinator.Reduce(in, b.Combinators, -1, combinator.Normal)
+		assert.Equal(t, td.expect, combinator.Canonical(actual), td.desc)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	b, ok := Lookup("ski")
+	assert.True(t, ok)
+	b.Combinators[0].Format = "{0}"
+	assert.Equal(t, "{0}{2}({1}{2})", SKI.Combinators[0].Format, "複製を返す")
+
+	_, ok = Lookup("foo")
+	assert.False(t, ok)
+}
+
+func TestMerge(t *testing.T) {
+	add := []combinator.Combinator{
+		{Name: "I", ArgsCount: 1, Format: "SKK{0}"},
+		{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+	}
+	actual := Merge(SKI.Combinators, add)
+	expect := []combinator.Combinator{
+		{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+		{Name: "K", ArgsCount: 2, Format: "{0}"},
+		{Name: "I", ArgsCount: 1, Format: "SKK{0}"},
+		{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+	}
+	assert.Equal(t, expect, actual)
+	assert.Equal(t, "{0}", SKI.Combinators[2].Format, "元の定義は変更しない")
+}
+
+func TestArithmeticConfig(t *testing.T) {
+	// 組み込みのArithmeticはconfig/combinator.jsonと同じ定義である
+	b, err := ioutil.ReadFile("../config/combinator.json")
+	assert.NoError(t, err)
+	var cs []combinator.Combinator
+	assert.NoError(t, json.Unmarshal(b, &cs))
+	assert.Equal(t, cs, Arithmetic.Combinators)
+}
@@ -108,25 +108,6 @@ func TestCalcCLCode(t *testing.T) {
 	}
 }
 
-func TestGetBracketCombinator(t *testing.T) {
-	type TD struct {
-		clcode string
-		expect string
-	}
-	tds := []TD{
-		TD{clcode: "(S)KIx", expect: "(S)"},
-		TD{clcode: "(SKI)x", expect: "(SKI)"},
-		TD{clcode: "(SKI)", expect: "(SKI)"},
-		TD{clcode: "S(SKI)", expect: "S"},
-		TD{clcode: "(SKI", expect: "(SKI"},
-	}
-	for _, v := range tds {
-		clcode, expect := v.clcode, v.expect
-		got1 := getBracketCombinator(clcode)
-		assert.Equal(t, expect, got1)
-	}
-}
-
 func TestCalcCLCode1Time(t *testing.T) {
 	assert.Equal(t, "xz(yz)", CalcCLCode1Time("Sxyz", cs))
 	assert.Equal(t, "xz(yz)!", CalcCLCode1Time("Sxyz!", cs))
@@ -137,198 +118,3 @@ func TestCalcCLCode1Time(t *testing.T) {
 	assert.Equal(t, "", CalcCLCode1Time("", cs))
 	assert.Equal(t, "Sxyz", CalcCLCode1Time("Sxyz", []Combinator{}))
 }
-
-func TestTrimBracket(t *testing.T) {
-	assert.Equal(t, "S", trimBracket("(S)"), "1つ括弧を外す")
-	assert.Equal(t, "S", trimBracket("((((S))))"), "ネストした括弧を外す")
-	assert.Equal(t, "(S)S", trimBracket("((((S)S)))"), "ネストした括弧を外す")
-	assert.Equal(t, "S(S)S", trimBracket("(((S(S)S)))"), "ネストした括弧を外す")
-	assert.Equal(t, "", trimBracket(""), "空のときは空を返す")
-	assert.Equal(t, "(S", trimBracket("(S"), "括弧不正のときはそのまま返す")
-	assert.Equal(t, "S)", trimBracket("S)"), "括弧不正のときはそのまま返す")
-}
-
-func TestCalcCombinatorArgs(t *testing.T) {
-	s := Combinator{
-		Name:      "S",
-		ArgsCount: 3,
-		Format:    "{0}{2}({1}{2})",
-	}
-	type TD struct {
-		inCLCode     []string
-		inCombinator Combinator
-		out          string
-		desc         string
-	}
-	tds := []TD{
-		TD{
-			inCLCode:     []string{"x", "y", "z"},
-			inCombinator: s,
-			out:          "xz(yz)",
-			desc:         "正常系",
-		},
-		TD{
-			inCLCode:     []string{""},
-			inCombinator: s,
-			out:          "",
-			desc:         "空文字だけのときは空文字を返す",
-		},
-		TD{
-			inCLCode:     []string{"x", "y"},
-			inCombinator: s,
-			out:          "xy",
-			desc:         "引数不足は処理せず結合して返す",
-		},
-		TD{
-			inCLCode:     []string{},
-			inCombinator: s,
-			out:          "",
-			desc:         "引数が空のときは空文字列を返す",
-		},
-		TD{
-			inCLCode:     nil,
-			inCombinator: s,
-			out:          "",
-			desc:         "引数がnilのときは空文字列を返す",
-		},
-		TD{
-			inCLCode:     []string{"x", "y"},
-			inCombinator: Combinator{},
-			out:          "",
-			desc:         "コンビネータが空のときはそのまま返す。",
-		},
-	}
-	for _, td := range tds {
-		expect, desc := td.out, td.desc
-		actual := calcCombinatorArgs(td.inCLCode, td.inCombinator)
-		assert.Equal(t, expect, actual, desc, td.inCLCode, td.inCombinator)
-	}
-}
-
-func TestSplitPrefixArgsSuffixCombinators(t *testing.T) {
-	var (
-		pref string
-		args []string
-		suff string
-	)
-
-	pref, args, suff = splitPrefixArgsSuffixCombinators("Sxyz", cs)
-	assert.Equal(t, "S", pref)
-	assert.Equal(t, []string{"x", "y", "z"}, args)
-	assert.Equal(t, "", suff)
-
-	pref, args, suff = splitPrefixArgsSuffixCombinators("Sxyz!", cs)
-	assert.Equal(t, "S", pref)
-	assert.Equal(t, []string{"x", "y", "z"}, args)
-	assert.Equal(t, "!", suff)
-
-}
-
-func TestGetPrefixCombinator(t *testing.T) {
-	type TD struct {
-		inCLCode      string
-		inCombinators []Combinator
-		expect        string
-		desc          string
-	}
-	tds := []TD{
-		TD{
-			inCLCode:      "Sabc",
-			inCombinators: cs,
-			expect:        "S",
-			desc:          "正常系",
-		},
-		TD{
-			inCLCode:      "(abc)x",
-			inCombinators: []Combinator{},
-			expect:        "(abc)",
-			desc:          "括弧で括られた文字列はコンビネータである",
-		},
-		TD{
-			inCLCode:      "(ab(xzy))x",
-			inCombinators: []Combinator{},
-			expect:        "(ab(xzy))",
-			desc:          "ネストした括弧もコンビネータである",
-		},
-		TD{
-			inCLCode:      "(abc)(xyz)",
-			inCombinators: []Combinator{},
-			expect:        "(abc)",
-			desc:          "括弧が連続しても別のコンビネータ",
-		},
-		TD{
-			inCLCode:      "abc",
-			inCombinators: []Combinator{Combinator{Name: "aAAAAAA"}},
-			expect:        "a",
-			desc:          "コンビネータにマッチしない",
-		},
-		TD{
-			inCLCode:      "Sabc",
-			inCombinators: []Combinator{Combinator{Name: "Sabc"}},
-			expect:        "Sabc",
-			desc:          "複数文字コンビネータ",
-		},
-	}
-	for _, td := range tds {
-		clcode, comb, desc, expect := td.inCLCode, td.inCombinators, td.desc, td.expect
-		actual := getPrefixCombinator(clcode, comb)
-		assert.Equal(t, expect, actual, desc, clcode, comb)
-	}
-}
-func TestGetCombinatorArgs(t *testing.T) {
-	type TD struct {
-		clcode string
-		cs     []Combinator
-		expect []string
-		desc   string
-	}
-	tds := []TD{
-		TD{
-			clcode: "Sxyz",
-			cs:     cs,
-			expect: []string{"x", "y", "z"},
-			desc:   "正常系",
-		},
-		TD{
-			clcode: "S(abc)(ab)(c)",
-			cs:     cs,
-			expect: []string{"(abc)", "(ab)", "(c)"},
-			desc:   "括弧で括られた文字列は1コンビネータ",
-		},
-		TD{
-			clcode: "S(abc)(ab)",
-			cs:     cs,
-			expect: []string{},
-			desc:   "引数が不足しているときは空配列を返す",
-		},
-		TD{
-			clcode: "",
-			cs:     cs,
-			expect: []string{},
-			desc:   "何も渡されないときは空配列を返す",
-		},
-		TD{
-			clcode: "Sxyz",
-			cs:     []Combinator{},
-			expect: []string{},
-			desc:   "定義済みコンビネータがない時は何も返さない",
-		},
-		TD{
-			clcode: "Ixyz",
-			cs:     cs,
-			expect: []string{"x"},
-			desc:   "Iコンビネータ",
-		},
-		TD{
-			clcode: "Zxyz",
-			cs:     cs,
-			expect: []string{},
-			desc:   "マッチするコンビネータがない場合は空配列を返す",
-		},
-	}
-	for _, td := range tds {
-		clcode, cs, expect, desc := td.clcode, td.cs, td.expect, td.desc
-		actual := getCombinatorArgs(clcode, cs)
-		assert.Equal(t, expect, actual, desc, clcode, cs)
-	}
-}
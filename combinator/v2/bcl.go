@@ -0,0 +1,94 @@
+package combinator
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseBCL はBCL(二進コンビネータ論理)の書式のCLCodeを解析して項を返す。
+// 00はK、01はS、1は続く2つの項の関数適用である。
+// 空白は読み飛ばす。
+// 解析できない場合は*ParseErrorを返す。
+func ParseBCL(clcode string, cs []Combinator) (Term, error) {
+	p := &parser{src: clcode, cs: cs}
+	p.skipSpace()
+	if len(p.src) <= p.pos {
+		return nil, p.errorf(p.pos, "項が空です。")
+	}
+	t, err := p.parseBCL()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf(p.pos, "項の後に余分な文字があります。")
+	}
+	return t, nil
+}
+
+// parseBCL はBCLの書式の項を1つ解析する。
+func (p *parser) parseBCL() (Term, error) {
+	bit, err := p.nextBit("関数適用の引数が足りません。")
+	if err != nil {
+		return nil, err
+	}
+	if bit == '1' {
+		f, err := p.parseBCL()
+		if err != nil {
+			return nil, err
+		}
+		a, err := p.parseBCL()
+		if err != nil {
+			return nil, err
+		}
+		return &App{Fun: f, Arg: a}, nil
+	}
+
+	start := p.pos - 1
+	bit, err = p.nextBit("コンビネータの符号が途中で終わっています。")
+	if err != nil {
+		return nil, err
+	}
+	name, argc := "K", 2
+	if bit == '1' {
+		name, argc = "S", 3
+	}
+	a, err := lookupAtom(name, argc, p.cs)
+	if err != nil {
+		return nil, p.errorf(start, "%s", err)
+	}
+	return a, nil
+}
+
+// nextBit は空白を読み飛ばして0か1を1文字読む。
+// 入力が終わっている場合はmsgの構文エラーを返す。
+func (p *parser) nextBit(msg string) (byte, error) {
+	p.skipSpace()
+	if len(p.src) <= p.pos {
+		return 0, p.errorf(p.pos, "%s", msg)
+	}
+	c := p.src[p.pos]
+	if c != '0' && c != '1' {
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		return 0, p.errorf(p.pos, "BCLの書式で使えない文字です。文字=%c", r)
+	}
+	p.pos++
+	return c, nil
+}
+
+// bclCodes はS、K、IのBCLの書式である。IはSKKとして変換する。
+var bclCodes = map[string]string{
+	"S": "01",
+	"K": "00",
+	"I": "11" + "01" + "00" + "00",
+}
+
+// FormatBCL は項をBCLの書式の文字列に変換する。
+// S、K、I以外のAtomを含む項は変換できないためエラーを返す。
+func FormatBCL(t Term) (string, error) {
+	var sb strings.Builder
+	if err := writeCode(&sb, t, "1", bclCodes, "BCL"); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
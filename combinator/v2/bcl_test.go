@@ -0,0 +1,73 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBCL(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "00", expect: "K", desc: "K"},
+		TD{clcode: "01", expect: "S", desc: "S"},
+		TD{clcode: "11010000", expect: "SKK", desc: "関数適用"},
+		TD{clcode: "1 01 100 00", expect: "S(KK)", desc: "空白を読み飛ばす"},
+	}
+	for _, td := range tds {
+		actual, err := ParseBCL(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc, td.clcode)
+	}
+
+	type TDE struct {
+		clcode string
+		cs     []Combinator
+		expect *ParseError
+		desc   string
+	}
+	tdes := []TDE{
+		TDE{clcode: " ", cs: cs, expect: &ParseError{Line: 1, Column: 2, Msg: "項が空です。"}, desc: "空"},
+		TDE{clcode: "101", cs: cs, expect: &ParseError{Line: 1, Column: 4, Msg: "関数適用の引数が足りません。"}, desc: "引数が足りない"},
+		TDE{clcode: "0", cs: cs, expect: &ParseError{Line: 1, Column: 2, Msg: "コンビネータの符号が途中で終わっています。"}, desc: "符号が途中で終わる"},
+		TDE{clcode: "0100", cs: cs, expect: &ParseError{Line: 1, Column: 3, Msg: "項の後に余分な文字があります。"}, desc: "余分な文字"},
+		TDE{clcode: "1012", cs: cs, expect: &ParseError{Line: 1, Column: 4, Msg: "BCLの書式で使えない文字です。文字=2"}, desc: "使えない文字"},
+		TDE{clcode: "1 01 00", cs: cs[1:], expect: &ParseError{Line: 1, Column: 3, Msg: "コンビネータS(引数3個)が定義されていません。"}, desc: "Sが定義されていない"},
+	}
+	for _, td := range tdes {
+		_, err := ParseBCL(td.clcode, td.cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestFormatBCL(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "SKK", expect: "11010000", desc: "関数適用"},
+		TD{clcode: "S(KK)", expect: "10110000", desc: "引数の関数適用"},
+		TD{clcode: "I", expect: "11010000", desc: "IはSKKとして変換する"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		actual, err := FormatBCL(in)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc)
+
+		// 出力した文字列を解析すると同じ振る舞いの項になる
+		back, err := ParseBCL(actual, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, behaviour(t, in), behaviour(t, back), td.desc)
+	}
+
+	_, err := FormatBCL(&Atom{Name: "x"})
+	assert.EqualError(t, err, "BCLの書式に変換できないコンビネータです。名前=x")
+}
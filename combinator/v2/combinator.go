@@ -0,0 +1,135 @@
+package combinator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Combinator はコンビネータである。
+// Paramsで引数名を宣言した場合は、Formatで{0}などの番号の代わりに{x}などの引数名を使える。
+type Combinator struct {
+	Name      string   `json:"name"`
+	ArgsCount int      `json:"argsCount"`
+	Format    string   `json:"format"`
+	Params    []string `json:"params,omitempty"`
+}
+
+// CalcCLCode は計算不可能になるまで計算した結果を返す。
+// nは計算するステップ数の上限で、-1の場合は上限なしで計算する。
+// 計算結果はCanonicalで文字列に変換する。
+func CalcCLCode(clcode string, cs []Combinator, n int, st Strategy) (string, error) {
+	return CalcCLCodeContext(context.Background(), clcode, cs, n, st)
+}
+
+// CalcCLCodeContext は計算不可能になるまで計算した結果を返す。
+// nは計算するステップ数の上限で、-1の場合は上限なしで計算する。
+// コンテキストがキャンセルされるか期限を過ぎた場合は、その時点の計算結果とコンテキストのエラーを返す。
+func CalcCLCodeContext(ctx context.Context, clcode string, cs []Combinator, n int, st Strategy) (string, error) {
+	t, err := Parse(clcode, cs)
+	if err != nil {
+		return "", err
+	}
+	r := Reducer{Combinators: cs, Strategy: st, MaxSteps: n, DetectCycle: true}
+	ret := r.ReduceContext(ctx, t)
+	switch ret.Outcome {
+	case Canceled, TimedOut:
+		return Canonical(ret.Term), ctx.Err()
+	}
+	return Canonical(ret.Term), nil
+}
+
+// CalcCLCode1Time は簡約戦略に従ってコンビネータを一度だけ計算する。
+// 括弧があっても展開して1回計算する。
+// 計算結果はCanonicalで文字列に変換する。
+func CalcCLCode1Time(clcode string, cs []Combinator, st Strategy) (string, error) {
+	t, err := Parse(clcode, cs)
+	if err != nil {
+		return "", err
+	}
+	t, _ = st.Step(t, cs)
+	return Canonical(t), nil
+}
+
+// Reduce は簡約戦略に従って、計算不可能になるか、nステップ計算するまで項を計算する。
+// nが-1の場合は上限なしで計算する。
+// 以前と同じ項が現れた場合はその時点で計算を終了する。
+func Reduce(t Term, cs []Combinator, n int, st Strategy) Term {
+	return ReduceContext(context.Background(), t, cs, n, st)
+}
+
+// ReduceContext は簡約戦略に従って、計算不可能になるか、nステップ計算するまで項を計算する。
+// nが-1の場合は上限なしで計算する。
+// 以前と同じ項が現れた場合や、コンテキストがキャンセルされるか期限を過ぎた場合はその時点で計算を終了する。
+func ReduceContext(ctx context.Context, t Term, cs []Combinator, n int, st Strategy) Term {
+	r := Reducer{Combinators: cs, Strategy: st, MaxSteps: n, DetectCycle: true}
+	return r.ReduceContext(ctx, t).Term
+}
+
+// Step は先頭のコンビネータを一度だけ計算する。
+// 先頭が括弧で括られていれば展開してから計算する。
+// 計算できなかった場合は元の項とfalseを返す。
+func Step(t Term, cs []Combinator) (Term, bool) {
+	head, args := Spine(t)
+	co, ok := headCombinator(head, len(args), cs)
+	if !ok {
+		return t, false
+	}
+	body, err := co.instantiate(args[:co.ArgsCount], cs)
+	if err != nil {
+		return t, false
+	}
+	return Apply(body, args[co.ArgsCount:]...), true
+}
+
+// findCombinator は名前に一致するコンビネータ定義を返す。
+func findCombinator(name string, cs []Combinator) (Combinator, bool) {
+	for _, c := range cs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Combinator{}, false
+}
+
+// instantiate はFormatの引数の埋め込み位置に引数の項を埋め込んだ項を返す。
+func (c Combinator) instantiate(args []Term, cs []Combinator) (Term, error) {
+	f, err := parseFormat(c.Format, c.Params, cs)
+	if err != nil {
+		return nil, err
+	}
+	return substitute(f, args), nil
+}
+
+// CheckFormat はFormatを解析できるかを返す。
+// 引数名の数が引数の数と一致しないか、引数名が重複している場合はエラーを返す。
+// 未定義の引数名や不正な番号の引数の埋め込み位置がある場合は*ParseErrorを返す。
+func (c Combinator) CheckFormat(cs []Combinator) error {
+	if 0 < len(c.Params) && len(c.Params) != c.ArgsCount {
+		return fmt.Errorf("引数名の数%dが引数の数%dと一致しません。", len(c.Params), c.ArgsCount)
+	}
+	for i, prm := range c.Params {
+		for _, prev := range c.Params[:i] {
+			if prm == prev {
+				return fmt.Errorf("引数名%sが重複しています。", prm)
+			}
+		}
+	}
+	_, err := parseFormat(c.Format, c.Params, cs)
+	return err
+}
+
+// substitute は項の中の引数の埋め込み位置を引数で置き換える。
+// 範囲外の番号の埋め込み位置は置き換えない。
+func substitute(t Term, args []Term) Term {
+	switch v := t.(type) {
+	case *hole:
+		if v.Index < len(args) {
+			return args[v.Index]
+		}
+	case *App:
+		return &App{Fun: substitute(v.Fun, args), Arg: substitute(v.Arg, args)}
+	case *Paren:
+		return &Paren{Term: substitute(v.Term, args)}
+	}
+	return t
+}
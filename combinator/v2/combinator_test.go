@@ -0,0 +1,178 @@
+package combinator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var cs = []Combinator{
+	Combinator{
+		Name:      "S",
+		ArgsCount: 3,
+		Format:    "{0}{2}({1}{2})",
+	},
+	Combinator{
+		Name:      "K",
+		ArgsCount: 2,
+		Format:    "{0}",
+	},
+	Combinator{
+		Name:      "I",
+		ArgsCount: 1,
+		Format:    "{0}",
+	},
+}
+
+func TestCalcCLCode(t *testing.T) {
+	type TD struct {
+		clcode string
+		cs     []Combinator
+		n      int
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			clcode: "Sxyz",
+			cs:     cs,
+			n:      -1,
+			expect: "xz(yz)",
+			desc:   "一度だけ計算する",
+		},
+		TD{
+			clcode: "SKII",
+			cs:     cs,
+			n:      -1,
+			expect: "I",
+			desc:   "最後まで計算する",
+		},
+		TD{
+			clcode: "((((SSSSS))))",
+			cs:     cs,
+			n:      -1,
+			expect: "SS(SSS)",
+			desc:   "多段ネストの計算をする",
+		},
+		TD{
+			clcode: "(Sxyz)xyz",
+			cs:     cs,
+			n:      -1,
+			expect: "xz(yz)xyz",
+			desc:   "先頭の括弧を展開して計算する",
+		},
+		TD{
+			clcode: "SSSKS",
+			cs:     cs,
+			n:      1,
+			expect: "SK(SK)S",
+			desc:   "一度だけ計算する",
+		},
+		TD{
+			clcode: "SKIx",
+			cs:     cs,
+			n:      0,
+			expect: "SKIx",
+			desc:   "一度も計算しない",
+		},
+		TD{
+			clcode: "Dxy",
+			cs: []Combinator{
+				Combinator{Name: "D", ArgsCount: 0, Format: "KI"},
+				Combinator{Name: "K", ArgsCount: 2, Format: "{0}"},
+				Combinator{Name: "I", ArgsCount: 1, Format: "{0}"},
+			},
+			n:      -1,
+			expect: "y",
+			desc:   "引数なしのコンビネータを展開する",
+		},
+	}
+	for _, td := range tds {
+		actual, err := CalcCLCode(td.clcode, td.cs, td.n, WeakHead)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc, td.clcode)
+	}
+
+	_, err := CalcCLCode("", cs, -1, WeakHead)
+	assert.Error(t, err, "空のCLCodeはエラー")
+}
+
+func TestCalcCLCodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual, err := CalcCLCodeContext(ctx, "Sxyz", cs, -1, WeakHead)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, "Sxyz", actual, "キャンセル済みの場合は計算しない")
+
+	actual, err = CalcCLCodeContext(context.Background(), "Sxyz", cs, -1, WeakHead)
+	assert.NoError(t, err)
+	assert.Equal(t, "xz(yz)", actual)
+}
+
+func TestCalcCLCode1Time(t *testing.T) {
+	f := func(clcode string, cs []Combinator) string {
+		s, err := CalcCLCode1Time(clcode, cs, WeakHead)
+		assert.NoError(t, err, clcode)
+		return s
+	}
+	assert.Equal(t, "xz(yz)", f("Sxyz", cs))
+	assert.Equal(t, "xz(yz)!", f("Sxyz!", cs))
+	assert.Equal(t, "xz(yz)", f("(S)xyz", cs))
+	assert.Equal(t, "Sxy", f("Sxy", cs))
+	assert.Equal(t, "S", f("S", cs))
+	assert.Equal(t, "Sxyz", f("Sxyz", []Combinator{}))
+}
+
+func TestStep(t *testing.T) {
+	in, err := Parse("K(Ix)y", cs)
+	assert.NoError(t, err)
+
+	out, ok := Step(in, cs)
+	assert.True(t, ok)
+	assert.Equal(t, "(Ix)", out.String())
+
+	out, ok = Step(out, cs)
+	assert.True(t, ok)
+	assert.Equal(t, "x", out.String())
+
+	_, ok = Step(out, cs)
+	assert.False(t, ok, "計算できないときはfalse")
+}
+
+func TestStepParams(t *testing.T) {
+	r := Combinator{Name: "R", ArgsCount: 3, Format: "{y}{z}{x}", Params: []string{"x", "y", "z"}}
+	in, err := Parse("Rabcd", append([]Combinator{r}, cs...))
+	assert.NoError(t, err)
+	out, ok := Step(in, append([]Combinator{r}, cs...))
+	assert.True(t, ok)
+	assert.Equal(t, "bcad", out.String())
+}
+
+func TestCheckFormat(t *testing.T) {
+	type TD struct {
+		c      Combinator
+		expect error
+		desc   string
+	}
+	tds := []TD{
+		TD{c: Combinator{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"}, expect: nil, desc: "番号"},
+		TD{c: Combinator{Name: "S", ArgsCount: 3, Format: "{x}{z}({y}{z})", Params: []string{"x", "y", "z"}}, expect: nil, desc: "引数名"},
+		TD{c: Combinator{Name: "S", ArgsCount: 3, Format: "{x}{z}({y}{w})", Params: []string{"x", "y", "z"}}, expect: &ParseError{Line: 1, Column: 11, Msg: "引数名wは定義されていません。"}, desc: "未定義の引数名"},
+		TD{c: Combinator{Name: "S", ArgsCount: 3, Format: "{a}"}, expect: &ParseError{Line: 1, Column: 1, Msg: "引数の番号が不正です。番号=a"}, desc: "引数名の宣言がない"},
+		TD{c: Combinator{Name: "K", ArgsCount: 3, Format: "{x}", Params: []string{"x", "y"}}, expect: fmt.Errorf("引数名の数2が引数の数3と一致しません。"), desc: "引数名の数が違う"},
+		TD{c: Combinator{Name: "K", ArgsCount: 2, Format: "{x}", Params: []string{"x", "x"}}, expect: fmt.Errorf("引数名xが重複しています。"), desc: "引数名の重複"},
+	}
+	for _, td := range tds {
+		err := td.c.CheckFormat(cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	f, err := parseFormat("{0}{2}({1}{2}){3}", nil, cs)
+	assert.NoError(t, err)
+	args := []Term{&Atom{Name: "x"}, &Atom{Name: "y"}, &Atom{Name: "z"}}
+	assert.Equal(t, "xz(yz){3}", substitute(f, args).String(), "範囲外の番号は置き換えない")
+}
@@ -0,0 +1,126 @@
+package combinator
+
+import (
+	"context"
+	"strconv"
+)
+
+// Decoder は計算結果の項を値として読み取る方法である。
+// 項に新しい変数を適用して計算し、計算結果の形から値を読み取る。
+type Decoder interface {
+	// Decode は項をrのコンビネータ定義と上限で計算し、値として読み取る。
+	// 簡約戦略はrの指定に関わらずNormalで計算する。
+	// 上限を超えるか、ctxがキャンセルされるか期限を過ぎた場合は読み取れなかったものとする。
+	// 読み取れなかった場合はfalseを返す。
+	Decode(ctx context.Context, t Term, r Reducer) (interface{}, bool)
+}
+
+var (
+	// Church はチャーチ数として項を読み取る。
+	// 項に新しい変数fとxを適用して計算し、xに適用されたfの数を返す。
+	Church Decoder = churchNumeral{}
+	// Bool はチャーチ真偽値として項を読み取る。
+	// 項に新しい変数tとfを適用して計算し、tになればtrue、fになればfalseを返す。
+	Bool Decoder = churchBool{}
+)
+
+// Decoders は値の読み取り方の名前と読み取り方の対応である。
+var Decoders = map[string]Decoder{
+	"church": Church,
+	"bool":   Bool,
+}
+
+type (
+	churchNumeral struct{}
+	churchBool    struct{}
+)
+
+func (churchNumeral) Decode(ctx context.Context, t Term, r Reducer) (interface{}, bool) {
+	vs := freshAtoms(t, r.Combinators, "f", "x")
+	f, x := vs[0], vs[1]
+	ret, ok := normalize(ctx, Apply(t, f, x), r)
+	if !ok {
+		return nil, false
+	}
+	var n int
+	for {
+		head, args := Spine(ret)
+		a, ok := head.(*Atom)
+		if !ok {
+			return nil, false
+		}
+		switch {
+		case a.Name == x.Name && len(args) == 0:
+			return n, true
+		case a.Name == f.Name && len(args) == 1:
+			n++
+			ret = args[0]
+		default:
+			return nil, false
+		}
+	}
+}
+
+func (churchBool) Decode(ctx context.Context, t Term, r Reducer) (interface{}, bool) {
+	vs := freshAtoms(t, r.Combinators, "t", "f")
+	tr, fl := vs[0], vs[1]
+	ret, ok := normalize(ctx, Apply(t, tr, fl), r)
+	if !ok {
+		return nil, false
+	}
+	switch {
+	case Equal(ret, tr):
+		return true, true
+	case Equal(ret, fl):
+		return false, true
+	}
+	return nil, false
+}
+
+// normalize は項をrのコンビネータ定義と上限で正規形まで計算する。
+// 正規形まで計算できなかった場合はfalseを返す。
+func normalize(ctx context.Context, t Term, r Reducer) (Term, bool) {
+	r.Strategy, r.DetectCycle, r.OnStep = Normal, true, nil
+	ret := r.ReduceContext(ctx, t)
+	switch ret.Outcome {
+	case NormalForm:
+		return ret.Term, true
+	case StepLimit:
+		// 上限ちょうどで正規形になっている場合がある
+		_, ok := Normal.Step(ret.Term, r.Combinators)
+		return ret.Term, !ok
+	}
+	return ret.Term, false
+}
+
+// freshAtoms は項にもコンビネータ定義にも現れない名前のAtomを返す。
+// 名前が使われている場合は末尾に数字を付ける。
+func freshAtoms(t Term, cs []Combinator, names ...string) []*Atom {
+	used := map[string]bool{}
+	for _, c := range cs {
+		used[c.Name] = true
+	}
+	markAtoms(t, used)
+
+	var ret []*Atom
+	for _, nm := range names {
+		s := nm
+		for i := 1; used[s]; i++ {
+			s = nm + strconv.Itoa(i)
+		}
+		used[s] = true
+		ret = append(ret, &Atom{Name: s})
+	}
+	return ret
+}
+
+// markAtoms は項に現れるAtomの名前をusedに追加する。
+func markAtoms(t Term, used map[string]bool) {
+	head, args := Spine(t)
+	if a, ok := head.(*Atom); ok {
+		used[a.Name] = true
+	}
+	for _, arg := range args {
+		markAtoms(arg, used)
+	}
+}
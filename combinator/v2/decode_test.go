@@ -0,0 +1,70 @@
+package combinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	b := append([]Combinator{Combinator{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"}}, cs...)
+	type TD struct {
+		clcode string
+		d      Decoder
+		expect interface{}
+		ok     bool
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "KI", d: Church, expect: 0, ok: true, desc: "チャーチ数:0"},
+		TD{clcode: "I", d: Church, expect: 1, ok: true, desc: "チャーチ数:1"},
+		TD{clcode: "SB(SB(SB(KI)))", d: Church, expect: 3, ok: true, desc: "チャーチ数:計算してから読み取る"},
+		TD{clcode: "S(S(KS)K)I", d: Church, expect: 2, ok: true, desc: "チャーチ数:BがなくてもSとKで表せる"},
+		TD{clcode: "K", d: Church, expect: nil, ok: false, desc: "チャーチ数:読み取れない"},
+		TD{clcode: "SB(KI)f", d: Church, expect: nil, ok: false, desc: "チャーチ数:項に現れる変数名は使わない"},
+		TD{clcode: "K", d: Bool, expect: true, ok: true, desc: "真偽値:true"},
+		TD{clcode: "SK", d: Bool, expect: false, ok: true, desc: "真偽値:false"},
+		TD{clcode: "KI", d: Bool, expect: false, ok: true, desc: "真偽値:0と同じ項"},
+		TD{clcode: "I", d: Bool, expect: nil, ok: false, desc: "真偽値:読み取れない"},
+		TD{clcode: "S", d: Bool, expect: nil, ok: false, desc: "真偽値:正規形が変数でない"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, b)
+		assert.NoError(t, err, td.desc)
+		actual, ok := td.d.Decode(context.Background(), in, Reducer{Combinators: b, MaxSteps: -1})
+		assert.Equal(t, td.expect, actual, td.desc, td.clcode)
+		assert.Equal(t, td.ok, ok, td.desc, td.clcode)
+	}
+}
+
+func TestDecodeStepLimit(t *testing.T) {
+	in, err := Parse("SII(SII)", cs)
+	assert.NoError(t, err)
+	_, ok := Church.Decode(context.Background(), in, Reducer{Combinators: cs, MaxSteps: 100})
+	assert.False(t, ok, "正規形まで計算できない")
+
+	in, err = Parse("I", cs)
+	assert.NoError(t, err)
+	actual, ok := Church.Decode(context.Background(), in, Reducer{Combinators: cs, MaxSteps: 1})
+	assert.Equal(t, 1, actual, "上限ちょうどで正規形になる")
+	assert.True(t, ok, "上限ちょうどで正規形になる")
+}
+
+func TestDecodeLimit(t *testing.T) {
+	// 恒等コンビネータの適用を取り除いても循環を検出できない項
+	in, err := Parse("S(SKK)(SKK)(S(SKK)(SKK))", cs)
+	assert.NoError(t, err)
+
+	_, ok := Church.Decode(context.Background(), in, Reducer{Combinators: cs, MaxSteps: -1, MaxSize: 50})
+	assert.False(t, ok, "項の大きさの上限を超える")
+
+	_, ok = Bool.Decode(context.Background(), in, Reducer{Combinators: cs, MaxSteps: -1, MaxDepth: 10})
+	assert.False(t, ok, "項の深さの上限を超える")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok = Church.Decode(ctx, in, Reducer{Combinators: cs, MaxSteps: -1})
+	assert.False(t, ok, "期限を過ぎる")
+}
@@ -0,0 +1,239 @@
+package combinator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseDefinitions は「S x y z = x z (y z)」のような等式で書かれたコンビネータ定義を解析する。
+// 左辺はコンビネータ名と引数名を空白で区切って並べ、引数の数は左辺の引数名の数とする。
+// 右辺は左辺の引数名とファイル内のコンビネータ名を最長一致で区切って解析し、
+// 引数名を{0}などの引数の埋め込み位置に置き換えてFormatとする。
+// 「#」から行末まではコメントとして読み飛ばす。
+// 解析できない場合は*ParseErrorを返す。
+func ParseDefinitions(src string) ([]Combinator, error) {
+	return ParseDefinitionsWith(src, nil)
+}
+
+// ParseDefinitionsWith はvisibleのコンビネータを右辺で参照できるものとして、
+// 等式で書かれたコンビネータ定義を解析する。
+// 右辺はvisibleのコンビネータ名も含めて最長一致で区切って解析する。
+// 引数名が空白や括弧で区切られずに定義されていない名前の文字と並んでいる場合は、
+// 他の名前の一部を引数名として解析している可能性があるため*ParseErrorを返す。
+func ParseDefinitionsWith(src string, visible []Combinator) ([]Combinator, error) {
+	p := &parser{src: src}
+	rules, err := p.parseRuleHeads()
+	if err != nil {
+		return nil, err
+	}
+
+	names := visible[:len(visible):len(visible)]
+	for _, r := range rules {
+		names = append(names, Combinator{Name: r.name})
+	}
+	var cs []Combinator
+	for _, r := range rules {
+		c, err := p.parseRuleBody(r, names)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+// rule は等式で書かれたコンビネータ定義の1行である。
+type rule struct {
+	name   string
+	params []string
+	// body は右辺の開始位置で、end は右辺の終了位置である。
+	body, end int
+}
+
+// parseRuleHeads は全ての行の左辺を解析する。
+func (p *parser) parseRuleHeads() ([]rule, error) {
+	var (
+		rules   []rule
+		defined = map[string]bool{}
+	)
+	for start := 0; start < len(p.src); {
+		end := strings.IndexByte(p.src[start:], '\n')
+		if end < 0 {
+			end = len(p.src)
+		} else {
+			end += start
+		}
+		next := end + 1
+
+		// コメントを除く
+		if i := strings.IndexByte(p.src[start:end], '#'); 0 <= i {
+			end = start + i
+		}
+		line := strings.TrimRightFunc(p.src[start:end], unicode.IsSpace)
+		end = start + len(line)
+		if strings.TrimSpace(line) == "" {
+			start = next
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, p.errorf(end, "「=」がありません。")
+		}
+		r := rule{body: start + eq + 1, end: end}
+		p.pos = start
+		for {
+			tok, pos := p.nextField(start + eq)
+			if tok == "" {
+				break
+			}
+			if i := strings.IndexAny(tok, "(){}"); 0 <= i {
+				return nil, p.errorf(pos+i, "左辺に使えない文字です。文字=%c", tok[i])
+			}
+			if r.name == "" {
+				if defined[tok] {
+					return nil, p.errorf(pos, "コンビネータ%sが重複して定義されています。", tok)
+				}
+				r.name = tok
+				continue
+			}
+			if tok == r.name {
+				return nil, p.errorf(pos, "引数名%sがコンビネータ名と同じです。", tok)
+			}
+			for _, prm := range r.params {
+				if tok == prm {
+					return nil, p.errorf(pos, "引数名%sが重複しています。", tok)
+				}
+			}
+			r.params = append(r.params, tok)
+		}
+		if r.name == "" {
+			return nil, p.errorf(start+eq, "コンビネータ名がありません。")
+		}
+		defined[r.name] = true
+		rules = append(rules, r)
+		start = next
+	}
+	return rules, nil
+}
+
+// nextField は現在位置からlimitまでの範囲で、空白で区切られた次の語とその位置を返す。
+// 語がない場合は空文字列を返す。
+func (p *parser) nextField(limit int) (string, int) {
+	for p.pos < limit {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:limit])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	start := p.pos
+	for p.pos < limit {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:limit])
+		if unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos], start
+}
+
+// parseRuleBody は右辺を解析してコンビネータ定義を返す。
+// namesはファイル内で定義されている全てのコンビネータ名である。
+func (p *parser) parseRuleBody(r rule, names []Combinator) (Combinator, error) {
+	body := p.src[r.body:r.end]
+	if strings.TrimSpace(body) == "" {
+		return Combinator{}, p.errorf(r.end, "右辺が空です。")
+	}
+	if i := strings.IndexAny(body, "{}="); 0 <= i {
+		return Combinator{}, p.errorf(r.body+i, "右辺に使えない文字です。文字=%c", body[i])
+	}
+
+	cs := names
+	for _, prm := range r.params {
+		cs = append(cs[:len(cs):len(cs)], Combinator{Name: prm})
+	}
+	if err := p.checkParamWords(r, cs); err != nil {
+		return Combinator{}, err
+	}
+	bp := &parser{src: p.src[:r.end], pos: r.body, cs: cs}
+	t, err := bp.parse()
+	if err != nil {
+		return Combinator{}, err
+	}
+	return Combinator{
+		Name:      r.name,
+		ArgsCount: len(r.params),
+		Format:    Canonical(toHoles(t, r.params)),
+	}, nil
+}
+
+// checkParamWords は右辺を空白と括弧で語に区切り、引数名と定義されていない名前の文字が
+// 同じ語に並んでいる場合に、語の中の最初の引数名の位置の*ParseErrorを返す。
+// 「church.<zero>」のように定義されていない名前の中の「c」を引数名として解析するのを防ぐ。
+func (p *parser) checkParamWords(r rule, cs []Combinator) error {
+	isParam := func(name string) bool {
+		for _, prm := range r.params {
+			if name == prm {
+				return true
+			}
+		}
+		return false
+	}
+	for pos := r.body; pos < r.end; {
+		end := pos
+		for end < r.end {
+			c, size := utf8.DecodeRuneInString(p.src[end:r.end])
+			if unicode.IsSpace(c) || c == '(' || c == ')' {
+				break
+			}
+			end += size
+		}
+		var (
+			param    = -1
+			unknown  bool
+			word     = p.src[pos:end]
+			paramStr string
+		)
+		for i := pos; i < end; {
+			nm := longestName(p.src[i:end], cs)
+			if nm == "" {
+				_, size := utf8.DecodeRuneInString(p.src[i:end])
+				unknown = true
+				i += size
+				continue
+			}
+			if param < 0 && isParam(nm) {
+				param, paramStr = i, nm
+			}
+			i += len(nm)
+		}
+		if 0 <= param && unknown {
+			return p.errorf(param, "引数名%sが定義されていない名前%sの一部として解析されます。", paramStr, word)
+		}
+		if end == pos {
+			_, size := utf8.DecodeRuneInString(p.src[pos:r.end])
+			end += size
+		}
+		pos = end
+	}
+	return nil
+}
+
+// toHoles は項の中の引数名のAtomを引数の埋め込み位置に置き換える。
+func toHoles(t Term, params []string) Term {
+	switch v := t.(type) {
+	case *Atom:
+		for i, prm := range params {
+			if v.Name == prm {
+				return &hole{Index: i}
+			}
+		}
+	case *App:
+		return &App{Fun: toHoles(v.Fun, params), Arg: toHoles(v.Arg, params)}
+	case *Paren:
+		return &Paren{Term: toHoles(v.Term, params)}
+	}
+	return t
+}
@@ -0,0 +1,73 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDefinitions(t *testing.T) {
+	src := `# SKI
+S x y z = x z (y z)
+K x y   = x   # 2番目の引数を捨てる
+
+I x = x
+D = C(BC(B(CI)K))
+T foo bar = bar foo
+<one> f x = f x
+R x y z = z(Q y)(D<one>x)
+`
+	expect := []Combinator{
+		Combinator{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+		Combinator{Name: "K", ArgsCount: 2, Format: "{0}"},
+		Combinator{Name: "I", ArgsCount: 1, Format: "{0}"},
+		Combinator{Name: "D", ArgsCount: 0, Format: "C(BC(B(CI)K))"},
+		Combinator{Name: "T", ArgsCount: 2, Format: "{1}{0}"},
+		Combinator{Name: "<one>", ArgsCount: 2, Format: "{0}{1}"},
+		Combinator{Name: "R", ArgsCount: 3, Format: "{2}(Q{1})(D<one>{0})"},
+	}
+	actual, err := ParseDefinitions(src)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, actual)
+}
+
+func TestParseDefinitionsError(t *testing.T) {
+	type TD struct {
+		src    string
+		expect *ParseError
+		desc   string
+	}
+	tds := []TD{
+		TD{src: "I x = x\nK x y\n", expect: &ParseError{Line: 2, Column: 6, Msg: "「=」がありません。"}, desc: "=がない"},
+		TD{src: "  = x", expect: &ParseError{Line: 1, Column: 3, Msg: "コンビネータ名がありません。"}, desc: "コンビネータ名がない"},
+		TD{src: "K x x = x", expect: &ParseError{Line: 1, Column: 5, Msg: "引数名xが重複しています。"}, desc: "引数名の重複"},
+		TD{src: "K K = K", expect: &ParseError{Line: 1, Column: 3, Msg: "引数名Kがコンビネータ名と同じです。"}, desc: "引数名とコンビネータ名が同じ"},
+		TD{src: "I x = x\n I y = y", expect: &ParseError{Line: 2, Column: 2, Msg: "コンビネータIが重複して定義されています。"}, desc: "コンビネータの重複"},
+		TD{src: "S(x) = x", expect: &ParseError{Line: 1, Column: 2, Msg: "左辺に使えない文字です。文字=("}, desc: "左辺の括弧"},
+		TD{src: "I x = # コメント", expect: &ParseError{Line: 1, Column: 6, Msg: "右辺が空です。"}, desc: "右辺が空"},
+		TD{src: "I x = {0}", expect: &ParseError{Line: 1, Column: 7, Msg: "右辺に使えない文字です。文字={"}, desc: "右辺の波括弧"},
+		TD{src: "I x = x\nS x y z = x z (y z", expect: &ParseError{Line: 2, Column: 15, Msg: "対応する閉じ括弧がありません。"}, desc: "右辺の括弧"},
+		TD{src: "F c = church.<one> c", expect: &ParseError{Line: 1, Column: 7, Msg: "引数名cが定義されていない名前church.<one>の一部として解析されます。"}, desc: "定義されていない名前の中の引数名"},
+		TD{src: "G s = K(<suc>s)", expect: &ParseError{Line: 1, Column: 10, Msg: "引数名sが定義されていない名前<suc>sの一部として解析されます。"}, desc: "括弧の中の定義されていない名前の中の引数名"},
+		TD{src: "あ x = x)", expect: &ParseError{Line: 1, Column: 8, Msg: "対応する開き括弧がありません。"}, desc: "桁は文字数で数える"},
+	}
+	for _, td := range tds {
+		_, err := ParseDefinitions(td.src)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestParseDefinitionsWith(t *testing.T) {
+	visible := []Combinator{
+		Combinator{Name: "church.<one>", ArgsCount: 0, Format: "church.<suc>church.<zero>"},
+		Combinator{Name: "<suc>", ArgsCount: 0, Format: "SB"},
+	}
+	src := "F c = church.<one> c\nG s = K(<suc>s)\n"
+	expect := []Combinator{
+		Combinator{Name: "F", ArgsCount: 1, Format: "church.<one>{0}"},
+		Combinator{Name: "G", ArgsCount: 1, Format: "K(<suc>{0})"},
+	}
+	actual, err := ParseDefinitionsWith(src, visible)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, actual, "読み込み済みのコンビネータ名を最長一致で区切る")
+}
@@ -0,0 +1,132 @@
+package combinator
+
+// Dependencies はコンビネータ定義の参照グラフを返す。
+// コンビネータ名ごとに、Formatで参照しているコンビネータ名を現れた順に重複なく返す。
+// 同じ名前の定義が複数ある場合は先に定義したものを使う。
+// 解析できないFormatの参照は含めない。
+func Dependencies(cs []Combinator) map[string][]string {
+	deps := map[string][]string{}
+	for _, c := range cs {
+		if _, ok := deps[c.Name]; ok {
+			continue
+		}
+		deps[c.Name] = nil
+		f, err := parseFormat(c.Format, c.Params, cs)
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		var walk func(t Term)
+		walk = func(t Term) {
+			head, args := Spine(t)
+			if a, ok := head.(*Atom); ok && !seen[a.Name] {
+				if _, ok := findCombinator(a.Name, cs); ok {
+					seen[a.Name] = true
+					deps[c.Name] = append(deps[c.Name], a.Name)
+				}
+			}
+			for _, arg := range args {
+				walk(arg)
+			}
+		}
+		walk(f)
+	}
+	return deps
+}
+
+// MacroCycles は引数のないコンビネータから出て戻る、展開が終わらない参照の循環を全て返す。
+// 引数のないコンビネータは現れたらすぐに展開されるため、循環していると展開が終わらない。
+// 引数のあるコンビネータはFormatで引数が揃って参照される場合だけ循環に含める。
+// 引数が足りない参照は引数が揃うまで展開されないため、経由する循環は含めない。
+// 循環はコンビネータ名の並びで、先に定義したコンビネータ名を先頭と末尾に置く。
+func MacroCycles(cs []Combinator) [][]string {
+	var names []string
+	graph := map[string][]string{}
+	for _, c := range cs {
+		if _, ok := graph[c.Name]; ok {
+			continue
+		}
+		graph[c.Name] = appliedReferences(c, cs)
+		if c.ArgsCount == 0 {
+			names = append(names, c.Name)
+		}
+	}
+
+	// 先に定義したコンビネータから順に、自身に戻る最短の経路を探す。
+	// 見つかった循環上のコンビネータは以降の探索の対象から外す。
+	var (
+		cycles [][]string
+		done   = map[string]bool{}
+	)
+	for _, start := range names {
+		if done[start] {
+			continue
+		}
+		cycle := shortestCycle(start, graph, done)
+		if cycle == nil {
+			continue
+		}
+		for _, name := range cycle {
+			done[name] = true
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// appliedReferences はFormatで引数が揃って参照されているコンビネータ名を、現れた順に重複なく返す。
+// 解析できないFormatの参照は含めない。
+func appliedReferences(c Combinator, cs []Combinator) []string {
+	f, err := parseFormat(c.Format, c.Params, cs)
+	if err != nil {
+		return nil
+	}
+	var (
+		refs []string
+		seen = map[string]bool{}
+		walk func(t Term)
+	)
+	walk = func(t Term) {
+		head, args := Spine(t)
+		if co, ok := headCombinator(head, len(args), cs); ok && !seen[co.Name] {
+			seen[co.Name] = true
+			refs = append(refs, co.Name)
+		}
+		for _, arg := range args {
+			walk(arg)
+		}
+	}
+	walk(f)
+	return refs
+}
+
+// shortestCycle はstartから出てstartに戻る最短の経路を幅優先探索で返す。
+// skipに含まれるコンビネータは通らない。経路がない場合はnilを返す。
+func shortestCycle(start string, graph map[string][]string, skip map[string]bool) []string {
+	prev := map[string]string{}
+	queue := []string{start}
+	for 0 < len(queue) {
+		name := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[name] {
+			if next == start {
+				cycle := []string{start}
+				for n := name; n != start; n = prev[n] {
+					cycle = append(cycle, n)
+				}
+				cycle = append(cycle, start)
+				// 逆順に辿ったので並びを戻す
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle
+			}
+			if _, ok := prev[next]; ok || skip[next] || next == start {
+				continue
+			}
+			prev[next] = name
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
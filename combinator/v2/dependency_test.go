@@ -0,0 +1,71 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	in := append([]Combinator{
+		Combinator{Name: "D", ArgsCount: 0, Format: "S(KI)(xS)"},
+		Combinator{Name: "Y", ArgsCount: 1, Format: "{0}(Y{0})"},
+		Combinator{Name: "E", ArgsCount: 0, Format: "D(("},
+	}, cs...)
+	expect := map[string][]string{
+		"D": []string{"S", "K", "I"},
+		"Y": []string{"Y"},
+		"E": nil,
+		"S": nil,
+		"K": nil,
+		"I": nil,
+	}
+	assert.Equal(t, expect, Dependencies(in))
+}
+
+func TestMacroCycles(t *testing.T) {
+	type TD struct {
+		cs     []Combinator
+		expect [][]string
+		desc   string
+	}
+	tds := []TD{
+		TD{cs: cs, expect: nil, desc: "循環なし"},
+		TD{cs: []Combinator{
+			Combinator{Name: "D", ArgsCount: 0, Format: "DD"},
+		}, expect: [][]string{[]string{"D", "D"}}, desc: "自身を参照する"},
+		TD{cs: []Combinator{
+			Combinator{Name: "A", ArgsCount: 0, Format: "B"},
+			Combinator{Name: "B", ArgsCount: 0, Format: "xC"},
+			Combinator{Name: "C", ArgsCount: 0, Format: "A(B)"},
+		}, expect: [][]string{[]string{"A", "B", "C", "A"}}, desc: "他の引数のないコンビネータを経由する"},
+		TD{cs: []Combinator{
+			Combinator{Name: "A", ArgsCount: 0, Format: "B"},
+			Combinator{Name: "B", ArgsCount: 0, Format: "A"},
+			Combinator{Name: "C", ArgsCount: 0, Format: "D"},
+			Combinator{Name: "D", ArgsCount: 0, Format: "C"},
+		}, expect: [][]string{[]string{"A", "B", "A"}, []string{"C", "D", "C"}}, desc: "複数の循環"},
+		TD{cs: []Combinator{
+			Combinator{Name: "Y", ArgsCount: 1, Format: "{0}(Y{0})"},
+			Combinator{Name: "A", ArgsCount: 0, Format: "F"},
+			Combinator{Name: "F", ArgsCount: 1, Format: "A{0}"},
+		}, expect: nil, desc: "引数のあるコンビネータを経由する循環は許す"},
+		TD{cs: []Combinator{
+			Combinator{Name: "D", ArgsCount: 0, Format: "Ex"},
+			Combinator{Name: "E", ArgsCount: 1, Format: "D"},
+		}, expect: [][]string{[]string{"D", "E", "D"}}, desc: "引数が揃って参照される引数のあるコンビネータを経由する循環"},
+		TD{cs: []Combinator{
+			Combinator{Name: "A", ArgsCount: 0, Format: "x(Fy)"},
+			Combinator{Name: "F", ArgsCount: 2, Format: "{1}A"},
+		}, expect: nil, desc: "引数が足りない参照を経由する循環は許す"},
+		TD{cs: append([]Combinator{
+			Combinator{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+			Combinator{Name: "<zero>", ArgsCount: 0, Format: "KI"},
+			Combinator{Name: "<suc>", ArgsCount: 0, Format: "SB"},
+			Combinator{Name: "<one>", ArgsCount: 0, Format: "<suc><zero>"},
+		}, cs...), expect: nil, desc: "引数のないコンビネータ同士の循環しない参照"},
+	}
+	for _, td := range tds {
+		assert.Equal(t, td.expect, MacroCycles(td.cs), td.desc)
+	}
+}
@@ -0,0 +1,161 @@
+package combinator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseIota はIotaの書式のCLCodeを解析して項を返す。
+// 「*」は続く2つの項の関数適用で、「i」か「ι」はλx.xSKを表すS(SI(KS))(KK)になる。
+// 空白は読み飛ばす。
+// 解析できない場合は*ParseErrorを返す。
+func ParseIota(clcode string, cs []Combinator) (Term, error) {
+	p := &parser{src: clcode, cs: cs}
+	p.skipSpace()
+	if len(p.src) <= p.pos {
+		return nil, p.errorf(p.pos, "項が空です。")
+	}
+	t, err := p.parseIota()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf(p.pos, "項の後に余分な文字があります。")
+	}
+	return t, nil
+}
+
+// parseIota はIotaの書式の項を1つ解析する。
+func (p *parser) parseIota() (Term, error) {
+	p.skipSpace()
+	if len(p.src) <= p.pos {
+		return nil, p.errorf(p.pos, "関数適用の引数が足りません。")
+	}
+	start := p.pos
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	switch r {
+	case '*':
+		p.pos += size
+		f, err := p.parseIota()
+		if err != nil {
+			return nil, err
+		}
+		a, err := p.parseIota()
+		if err != nil {
+			return nil, err
+		}
+		return &App{Fun: f, Arg: a}, nil
+	case 'i', 'ι':
+		t, err := iotaTerm(p.cs)
+		if err != nil {
+			return nil, p.errorf(start, "%s", err)
+		}
+		p.pos += size
+		return t, nil
+	}
+	return nil, p.errorf(start, "Iotaの書式で使えない文字です。文字=%c", r)
+}
+
+// iotaTerm はλx.xSKを表す項S(SI(KS))(KK)を返す。
+func iotaTerm(cs []Combinator) (Term, error) {
+	s, k, i, err := ski(cs)
+	if err != nil {
+		return nil, err
+	}
+	return &Paren{Term: Apply(s, &Paren{Term: Apply(s, i, &Paren{Term: Apply(k, s)})}, &Paren{Term: Apply(k, k)})}, nil
+}
+
+// ParseJot はJotの書式のCLCodeを解析して項を返す。
+// 空文字列はIで、項wの後の0はwSK、1はS(Kw)になる。
+// 空白は読み飛ばす。
+// 解析できない場合は*ParseErrorを返す。
+func ParseJot(clcode string, cs []Combinator) (Term, error) {
+	p := &parser{src: clcode, cs: cs}
+	s, k, i, err := ski(cs)
+	if err != nil {
+		return nil, p.errorf(0, "%s", err)
+	}
+	var t Term = i
+	for p.skipSpace(); p.pos < len(p.src); p.skipSpace() {
+		switch p.src[p.pos] {
+		case '0':
+			t = Apply(t, s, k)
+		case '1':
+			t = &App{Fun: s, Arg: &App{Fun: k, Arg: t}}
+		default:
+			r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+			return nil, p.errorf(p.pos, "Jotの書式で使えない文字です。文字=%c", r)
+		}
+		p.pos++
+	}
+	return t, nil
+}
+
+// ski はコンビネータS、K、Iを返す。
+func ski(cs []Combinator) (*Atom, *Atom, *Atom, error) {
+	s, err := lookupAtom("S", 3, cs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	k, i, err := ki(cs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s, k, i, nil
+}
+
+// iotaCodes はS、K、IのIotaの書式である。
+var iotaCodes = map[string]string{
+	"S": "*i*i*i*ii",
+	"K": "*i*i*ii",
+	"I": "*ii",
+}
+
+// jotCodes はS、K、IのJotの書式である。IはSKKとして変換する。
+var jotCodes = map[string]string{
+	"S": "11111000",
+	"K": "11100",
+	"I": "11" + "11111000" + "11100" + "11100",
+}
+
+// FormatIota は項をIotaの書式の文字列に変換する。
+// S、K、I以外のAtomを含む項は変換できないためエラーを返す。
+func FormatIota(t Term) (string, error) {
+	var sb strings.Builder
+	if err := writeCode(&sb, t, "*", iotaCodes, "Iota"); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatJot は項をJotの書式の文字列に変換する。
+// 関数適用FAは1の後にFとAを続けて出力する。
+// S、K、I以外のAtomを含む項は変換できないためエラーを返す。
+func FormatJot(t Term) (string, error) {
+	var sb strings.Builder
+	if err := writeCode(&sb, t, "1", jotCodes, "Jot"); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeCode は関数適用を前置記法で、AtomをcodesのS、K、Iの書式で書き込む。
+func writeCode(sb *strings.Builder, t Term, app string, codes map[string]string, syntax string) error {
+	switch v := unparen(t).(type) {
+	case *App:
+		sb.WriteString(app)
+		if err := writeCode(sb, v.Fun, app, codes, syntax); err != nil {
+			return err
+		}
+		return writeCode(sb, v.Arg, app, codes, syntax)
+	case *Atom:
+		if code, ok := codes[v.Name]; ok {
+			sb.WriteString(code)
+			return nil
+		}
+		return fmt.Errorf("%sの書式に変換できないコンビネータです。名前=%s", syntax, v.Name)
+	}
+	return fmt.Errorf("%sの書式に変換できない項です。項=%s", syntax, t)
+}
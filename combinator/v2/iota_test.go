@@ -0,0 +1,111 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIota(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "i", expect: "S(SI(KS))(KK)", desc: "ι"},
+		TD{clcode: "*ιi", expect: "S(SI(KS))(KK)(S(SI(KS))(KK))", desc: "関数適用"},
+		TD{clcode: "* i i", expect: "S(SI(KS))(KK)(S(SI(KS))(KK))", desc: "空白を読み飛ばす"},
+	}
+	for _, td := range tds {
+		actual, err := ParseIota(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc, td.clcode)
+	}
+
+	type TDE struct {
+		clcode string
+		expect *ParseError
+		desc   string
+	}
+	tdes := []TDE{
+		TDE{clcode: "", expect: &ParseError{Line: 1, Column: 1, Msg: "項が空です。"}, desc: "空"},
+		TDE{clcode: "*i", expect: &ParseError{Line: 1, Column: 3, Msg: "関数適用の引数が足りません。"}, desc: "引数が足りない"},
+		TDE{clcode: "*iii", expect: &ParseError{Line: 1, Column: 4, Msg: "項の後に余分な文字があります。"}, desc: "余分な文字"},
+		TDE{clcode: "*ix", expect: &ParseError{Line: 1, Column: 3, Msg: "Iotaの書式で使えない文字です。文字=x"}, desc: "使えない文字"},
+	}
+	for _, td := range tdes {
+		_, err := ParseIota(td.clcode, cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestParseJot(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "", expect: "I", desc: "空文字列"},
+		TD{clcode: "0", expect: "ISK", desc: "0"},
+		TD{clcode: "1", expect: "S(KI)", desc: "1"},
+		TD{clcode: "1 0", expect: "S(KI)SK", desc: "空白を読み飛ばす"},
+	}
+	for _, td := range tds {
+		actual, err := ParseJot(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc, td.clcode)
+	}
+
+	_, err := ParseJot("102", cs)
+	assert.Equal(t, &ParseError{Line: 1, Column: 3, Msg: "Jotの書式で使えない文字です。文字=2"}, err)
+}
+
+func TestFormatIotaJot(t *testing.T) {
+	// 変換した文字列を解析して計算すると、元の項と同じ振る舞いになる
+	type TD struct {
+		clcode string
+		iota   string
+		jot    string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "K", iota: "*i*i*ii", jot: "11100", desc: "K"},
+		TD{clcode: "S", iota: "*i*i*i*ii", jot: "11111000", desc: "S"},
+		TD{clcode: "I", iota: "*ii", jot: "11111110001110011100", desc: "I"},
+		TD{clcode: "SKK", iota: "***i*i*i*ii*i*i*ii*i*i*ii", jot: "11111110001110011100", desc: "関数適用"},
+		TD{clcode: "S(K(SI))K", iota: "***i*i*i*ii**i*i*ii**i*i*i*ii*ii*i*i*ii", jot: "11111110001111001111110001111111000111001110011100", desc: "引数の関数適用"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+
+		iota, err := FormatIota(in)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.iota, iota, td.desc)
+		back, err := ParseIota(iota, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, behaviour(t, in), behaviour(t, back), td.desc, "iota")
+
+		jot, err := FormatJot(in)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.jot, jot, td.desc)
+		back, err = ParseJot(jot, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, behaviour(t, in), behaviour(t, back), td.desc, "jot")
+	}
+
+	_, err := FormatIota(&App{Fun: &Atom{Name: "S"}, Arg: &Atom{Name: "x"}})
+	assert.EqualError(t, err, "Iotaの書式に変換できないコンビネータです。名前=x")
+	_, err = FormatJot(&Atom{Name: "B"})
+	assert.EqualError(t, err, "Jotの書式に変換できないコンビネータです。名前=B")
+}
+
+// behaviour は項に変数を3つ適用して正規形まで計算した結果を返す。
+func behaviour(t *testing.T, in Term) string {
+	r := Reducer{Combinators: cs, Strategy: Normal, MaxSteps: 1000, DetectCycle: true}
+	ret := r.Reduce(Apply(in, &Atom{Name: "x"}, &Atom{Name: "y"}, &Atom{Name: "z"}))
+	assert.Equal(t, NormalForm, ret.Outcome)
+	return Canonical(ret.Term)
+}
@@ -0,0 +1,137 @@
+package combinator
+
+import "fmt"
+
+// Encoding は#5、#true、#falseのようなリテラルを項に展開する方法である。
+// 展開した項はコンビネータ定義にあるコンビネータだけを使う。
+type Encoding interface {
+	// Number は自然数nを表す項を返す。
+	// 展開に必要なコンビネータが定義されていない場合はエラーを返す。
+	Number(n int, cs []Combinator) (Term, error)
+	// Bool は真偽値bを表す項を返す。
+	// 展開に必要なコンビネータが定義されていない場合はエラーを返す。
+	Bool(b bool, cs []Combinator) (Term, error)
+}
+
+var (
+	// ChurchEncoding はチャーチ数とチャーチ真偽値に展開する。
+	// 0はKI、n+1はSB(n)に展開する。Bが定義されていない場合はS(S(KS)K)(n)に展開する。
+	// trueはK、falseはKIに展開する。
+	ChurchEncoding Encoding = churchEncoding{}
+	// ScottEncoding はスコット数とスコット真偽値に展開する。
+	// 0はK、n+1はK(CI(n))に展開する。Cが定義されていない場合はK(SI(K(n)))に展開する。
+	// trueはK、falseはKIに展開する。
+	ScottEncoding Encoding = scottEncoding{}
+)
+
+// MaxNumberLiteral は#5のようなリテラルで書ける自然数の上限である。
+// 展開した項の大きさは自然数に比例するため、上限を超えるリテラルは展開せずにエラーにする。
+const MaxNumberLiteral = 10000
+
+// Encodings はリテラルの展開方法の名前と展開方法の対応である。
+var Encodings = map[string]Encoding{
+	"church": ChurchEncoding,
+	"scott":  ScottEncoding,
+}
+
+type (
+	churchEncoding struct{}
+	scottEncoding  struct{}
+)
+
+func (churchEncoding) Number(n int, cs []Combinator) (Term, error) {
+	k, i, err := ki(cs)
+	if err != nil {
+		return nil, err
+	}
+	suc, err := churchSuc(cs)
+	if err != nil {
+		return nil, err
+	}
+	var t Term = &App{Fun: k, Arg: i}
+	for ; 0 < n; n-- {
+		t = &App{Fun: suc, Arg: t}
+	}
+	return t, nil
+}
+
+func (churchEncoding) Bool(b bool, cs []Combinator) (Term, error) {
+	return churchBoolTerm(b, cs)
+}
+
+func (scottEncoding) Number(n int, cs []Combinator) (Term, error) {
+	k, i, err := ki(cs)
+	if err != nil {
+		return nil, err
+	}
+	// λs.s nを表す項を返す
+	var pair func(t Term) Term
+	if c, err := lookupAtom("C", 3, cs); err == nil {
+		pair = func(t Term) Term { return Apply(c, i, t) }
+	} else {
+		s, err := lookupAtom("S", 3, cs)
+		if err != nil {
+			return nil, err
+		}
+		pair = func(t Term) Term { return Apply(s, i, &App{Fun: k, Arg: t}) }
+	}
+	var t Term = k
+	for ; 0 < n; n-- {
+		t = &App{Fun: k, Arg: pair(t)}
+	}
+	return t, nil
+}
+
+func (scottEncoding) Bool(b bool, cs []Combinator) (Term, error) {
+	return churchBoolTerm(b, cs)
+}
+
+// churchSuc はチャーチ数の後者関数を表す項を返す。
+func churchSuc(cs []Combinator) (Term, error) {
+	s, err := lookupAtom("S", 3, cs)
+	if err != nil {
+		return nil, err
+	}
+	if b, err := lookupAtom("B", 3, cs); err == nil {
+		return &App{Fun: s, Arg: b}, nil
+	}
+	k, err := lookupAtom("K", 2, cs)
+	if err != nil {
+		return nil, err
+	}
+	return &App{Fun: s, Arg: Apply(s, &App{Fun: k, Arg: s}, k)}, nil
+}
+
+// churchBoolTerm はチャーチ真偽値を表す項を返す。
+func churchBoolTerm(b bool, cs []Combinator) (Term, error) {
+	k, i, err := ki(cs)
+	if err != nil {
+		return nil, err
+	}
+	if b {
+		return k, nil
+	}
+	return &App{Fun: k, Arg: i}, nil
+}
+
+// ki はコンビネータKとIを返す。
+func ki(cs []Combinator) (*Atom, *Atom, error) {
+	k, err := lookupAtom("K", 2, cs)
+	if err != nil {
+		return nil, nil, err
+	}
+	i, err := lookupAtom("I", 1, cs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, i, nil
+}
+
+// lookupAtom は名前と引数の数が一致するコンビネータのAtomを返す。
+// 定義されていない場合はエラーを返す。
+func lookupAtom(name string, argc int, cs []Combinator) (*Atom, error) {
+	if c, ok := findCombinator(name, cs); ok && c.ArgsCount == argc {
+		return &Atom{Name: name}, nil
+	}
+	return nil, fmt.Errorf("コンビネータ%s(引数%d個)が定義されていません。", name, argc)
+}
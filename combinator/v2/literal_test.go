@@ -0,0 +1,58 @@
+package combinator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	var (
+		b  = append([]Combinator{Combinator{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"}}, cs...)
+		bc = append([]Combinator{Combinator{Name: "C", ArgsCount: 3, Format: "{0}{2}{1}"}}, b...)
+	)
+	type TD struct {
+		e      Encoding
+		n      int
+		cs     []Combinator
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{e: ChurchEncoding, n: 0, cs: cs, expect: "KI", desc: "チャーチ数:0"},
+		TD{e: ChurchEncoding, n: 2, cs: b, expect: "SB(SB(KI))", desc: "チャーチ数:Bを使う"},
+		TD{e: ChurchEncoding, n: 2, cs: cs, expect: "S(S(KS)K)(S(S(KS)K)(KI))", desc: "チャーチ数:Bがない"},
+		TD{e: ScottEncoding, n: 0, cs: cs, expect: "K", desc: "スコット数:0"},
+		TD{e: ScottEncoding, n: 2, cs: bc, expect: "K(CI(K(CIK)))", desc: "スコット数:Cを使う"},
+		TD{e: ScottEncoding, n: 1, cs: cs, expect: "K(SI(KK))", desc: "スコット数:Cがない"},
+	}
+	for _, td := range tds {
+		actual, err := td.e.Number(td.n, td.cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc)
+	}
+
+	for _, e := range []Encoding{ChurchEncoding, ScottEncoding} {
+		tr, err := e.Bool(true, cs)
+		assert.NoError(t, err)
+		assert.Equal(t, "K", Canonical(tr))
+		fl, err := e.Bool(false, cs)
+		assert.NoError(t, err)
+		assert.Equal(t, "KI", Canonical(fl))
+	}
+
+	_, err := ChurchEncoding.Number(1, cs[1:])
+	assert.EqualError(t, err, "コンビネータS(引数3個)が定義されていません。")
+}
+
+func TestEncodingDecode(t *testing.T) {
+	// チャーチ数に展開した項はチャーチ数として読み取れる
+	for n := 0; n < 5; n++ {
+		in, err := ChurchEncoding.Number(n, cs)
+		assert.NoError(t, err)
+		actual, ok := Church.Decode(context.Background(), in, Reducer{Combinators: cs, MaxSteps: -1})
+		assert.True(t, ok)
+		assert.Equal(t, n, actual)
+	}
+}
@@ -0,0 +1,81 @@
+package combinator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamespaceSeparator は名前空間とコンビネータ名の区切りである。
+const NamespaceSeparator = "."
+
+// Qualified はコンビネータ名を名前空間nsで修飾した「ns.名前」を返す。
+func Qualified(ns, name string) string {
+	return ns + NamespaceSeparator + name
+}
+
+// Qualify はコンビネータ定義の名前を名前空間nsで修飾した複製を返す。
+// Formatで参照しているcsのコンビネータ名も修飾する。
+// Formatはvisibleとcsのコンビネータ名を最長一致で区切って解析するため、
+// visibleにある「church.<zero>」のような他の名前空間の修飾名は修飾しない。
+// 名前空間が空か使えない文字を含む場合、Formatを解析できない場合はエラーを返す。
+func Qualify(ns string, cs, visible []Combinator) ([]Combinator, error) {
+	if ns == "" {
+		return nil, fmt.Errorf("名前空間が空です。")
+	}
+	if i := strings.IndexFunc(ns, invalidNamespaceRune); 0 <= i {
+		return nil, fmt.Errorf("名前空間%sに使えない文字「%c」が含まれています。", ns, []rune(ns[i:])[0])
+	}
+
+	local := map[string]bool{}
+	for _, c := range cs {
+		local[c.Name] = true
+	}
+	all := append(visible[:len(visible):len(visible)], cs...)
+	var ret []Combinator
+	for _, c := range cs {
+		f, err := parseFormat(c.Format, c.Params, all)
+		if err != nil {
+			return nil, fmt.Errorf("コンビネータ%s: %v", c.Name, err)
+		}
+		c.Name = Qualified(ns, c.Name)
+		c.Format = Canonical(qualifyAtoms(f, ns, local))
+		c.Params = nil
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
+
+// Unqualify は名前空間nsで修飾されたコンビネータ定義を、修飾を除いた名前の定義として返す。
+// Formatは修飾した名前のまま参照する。
+func Unqualify(ns string, cs []Combinator) []Combinator {
+	prefix := Qualified(ns, "")
+	var ret []Combinator
+	for _, c := range cs {
+		if !strings.HasPrefix(c.Name, prefix) || c.Name == prefix {
+			continue
+		}
+		c.Name = strings.TrimPrefix(c.Name, prefix)
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// invalidNamespaceRune は名前空間に使えない文字かを返す。
+func invalidNamespaceRune(r rune) bool {
+	return invalidNameRune(r) || strings.ContainsRune(NamespaceSeparator, r)
+}
+
+// qualifyAtoms は項の中のlocalに含まれる名前のAtomを名前空間nsで修飾する。
+func qualifyAtoms(t Term, ns string, local map[string]bool) Term {
+	switch v := t.(type) {
+	case *Atom:
+		if local[v.Name] {
+			return &Atom{Name: Qualified(ns, v.Name)}
+		}
+	case *App:
+		return &App{Fun: qualifyAtoms(v.Fun, ns, local), Arg: qualifyAtoms(v.Arg, ns, local)}
+	case *Paren:
+		return &Paren{Term: qualifyAtoms(v.Term, ns, local)}
+	}
+	return t
+}
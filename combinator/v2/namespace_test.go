@@ -0,0 +1,80 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualify(t *testing.T) {
+	church := []Combinator{
+		Combinator{Name: "B", ArgsCount: 3, Format: "{x}({y}{z})", Params: []string{"x", "y", "z"}},
+		Combinator{Name: "<zero>", ArgsCount: 0, Format: "KI"},
+		Combinator{Name: "<suc>", ArgsCount: 0, Format: "SB"},
+		Combinator{Name: "<one>", ArgsCount: 0, Format: "<suc><zero>"},
+	}
+	actual, err := Qualify("church", church, cs)
+	assert.NoError(t, err)
+	assert.Equal(t, []Combinator{
+		Combinator{Name: "church.B", ArgsCount: 3, Format: "{0}({1}{2})"},
+		Combinator{Name: "church.<zero>", ArgsCount: 0, Format: "KI"},
+		Combinator{Name: "church.<suc>", ArgsCount: 0, Format: "Schurch.B"},
+		Combinator{Name: "church.<one>", ArgsCount: 0, Format: "church.<suc>church.<zero>"},
+	}, actual, "同じ定義内の参照だけを修飾する")
+
+	// 他の名前空間の修飾名は修飾しない
+	visible := append(actual, cs...)
+	scott := []Combinator{
+		Combinator{Name: "<zero>", ArgsCount: 0, Format: "K"},
+		Combinator{Name: "<c0>", ArgsCount: 0, Format: "church.<zero><zero>"},
+	}
+	actual, err = Qualify("scott", scott, visible)
+	assert.NoError(t, err)
+	assert.Equal(t, []Combinator{
+		Combinator{Name: "scott.<zero>", ArgsCount: 0, Format: "K"},
+		Combinator{Name: "scott.<c0>", ArgsCount: 0, Format: "church.<zero>scott.<zero>"},
+	}, actual, "他の名前空間の修飾名")
+
+	type TD struct {
+		ns     string
+		cs     []Combinator
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{ns: "", cs: church, expect: "名前空間が空です。", desc: "名前空間が空"},
+		TD{ns: "a.b", cs: church, expect: "名前空間a.bに使えない文字「.」が含まれています。", desc: "区切り文字を含む"},
+		TD{ns: "a b", cs: church, expect: "名前空間a bに使えない文字「 」が含まれています。", desc: "空白を含む"},
+		TD{ns: "a", cs: []Combinator{Combinator{Name: "X", ArgsCount: 0, Format: "(K"}}, expect: "コンビネータX: 1:1: 対応する閉じ括弧がありません。", desc: "Formatを解析できない"},
+	}
+	for _, td := range tds {
+		_, err := Qualify(td.ns, td.cs, cs)
+		assert.EqualError(t, err, td.expect, td.desc)
+	}
+}
+
+func TestUnqualify(t *testing.T) {
+	in := []Combinator{
+		Combinator{Name: "K", ArgsCount: 2, Format: "{0}"},
+		Combinator{Name: "church.<zero>", ArgsCount: 0, Format: "KI"},
+		Combinator{Name: "church.<one>", ArgsCount: 0, Format: "church.<suc>church.<zero>"},
+		Combinator{Name: "churchx.<zero>", ArgsCount: 0, Format: "K"},
+	}
+	assert.Equal(t, []Combinator{
+		Combinator{Name: "<zero>", ArgsCount: 0, Format: "KI"},
+		Combinator{Name: "<one>", ArgsCount: 0, Format: "church.<suc>church.<zero>"},
+	}, Unqualify("church", in))
+}
+
+func TestParseQualified(t *testing.T) {
+	in := append([]Combinator{
+		Combinator{Name: "<zero>", ArgsCount: 0, Format: "K"},
+		Combinator{Name: "church.<zero>", ArgsCount: 0, Format: "KI"},
+	}, cs...)
+	actual, err := CalcCLCode("church.<zero>xy", in, -1, WeakHead)
+	assert.NoError(t, err)
+	assert.Equal(t, "y", actual, "修飾名を最長一致で解析する")
+	actual, err = CalcCLCode("<zero>xy", in, -1, WeakHead)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", actual, "修飾なしの名前")
+}
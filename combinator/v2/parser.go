@@ -0,0 +1,310 @@
+package combinator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseError はCLCodeの構文エラーである。
+type ParseError struct {
+	// File は解析したファイル名である。ファイル以外を解析した場合は空文字列である。
+	File string
+	// Line は構文エラーの行番号である。1から数える。
+	Line int
+	// Column は構文エラーの桁番号である。1から数え、1文字を1桁とする。
+	Column int
+	// Msg は構文エラーの内容である。
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// Parse はCLCodeを解析して項を返す。
+// 定義済みコンビネータの名前は複数文字でも1つの項として扱い、
+// それ以外の文字は1文字ずつ別の項として扱う。
+// 解析できない場合は*ParseErrorを返す。
+func Parse(clcode string, cs []Combinator) (Term, error) {
+	return Parser{}.Parse(clcode, cs)
+}
+
+// ParseSpaced は空白か括弧で区切られた識別子を1つの項としてCLCodeを解析する。
+// 「S foo bar baz」はS、foo、bar、bazの4つの項の関数適用になる。
+// Printer{Spaced: true}で出力した文字列はこの関数で解析できる。
+// 解析できない場合は*ParseErrorを返す。
+func ParseSpaced(clcode string, cs []Combinator) (Term, error) {
+	return Parser{Spaced: true}.Parse(clcode, cs)
+}
+
+// Parser はCLCodeの解析方法である。
+// ゼロ値ではParseと同じ方法で解析する。
+type Parser struct {
+	// Spaced がtrueの場合は空白か括弧で区切られた識別子を1つの項として解析する。
+	Spaced bool
+	// Literals がnilでない場合は#5、#true、#falseのようなリテラルを項に展開する。
+	Literals Encoding
+}
+
+// Parse はCLCodeを解析して項を返す。
+// 解析できない場合は*ParseErrorを返す。
+func (ps Parser) Parse(clcode string, cs []Combinator) (Term, error) {
+	p := &parser{src: clcode, cs: cs, spaced: ps.Spaced, literals: ps.Literals}
+	return p.parse()
+}
+
+// parseFormat はコンビネータ定義のFormatを解析して項を返す。
+// {0}などの引数の埋め込み位置はholeとして解析する。
+// {x}などの引数名の埋め込み位置は、paramsでの引数名の位置を番号とする。
+func parseFormat(format string, params []string, cs []Combinator) (Term, error) {
+	p := &parser{src: format, cs: cs, template: true, params: params}
+	return p.parse()
+}
+
+// parser はCLCodeの構文解析器である。
+type parser struct {
+	src      string
+	pos      int
+	cs       []Combinator
+	template bool
+	params   []string
+	spaced   bool
+	literals Encoding
+}
+
+// parse は入力全体を1つの項として解析する。
+func (p *parser) parse() (Term, error) {
+	t, err := p.parseSeq()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf(p.pos, "対応する開き括弧がありません。")
+	}
+	if t == nil {
+		return nil, p.errorf(p.pos, "項が空です。")
+	}
+	return t, nil
+}
+
+// parseSeq は閉じ括弧か入力の終わりまでの項の並びを左結合の関数適用として解析する。
+// 項がひとつもない場合はnilを返す。
+func (p *parser) parseSeq() (Term, error) {
+	var t Term
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] == ')' {
+			return t, nil
+		}
+		item, err := p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			t = item
+			continue
+		}
+		t = &App{Fun: t, Arg: item}
+	}
+}
+
+// parseItem は括弧で括られた項、引数の埋め込み位置、またはAtomを1つ解析する。
+func (p *parser) parseItem() (Term, error) {
+	start := p.pos
+	switch p.src[p.pos] {
+	case '(':
+		p.pos++
+		t, err := p.parseSeq()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf(start, "対応する閉じ括弧がありません。")
+		}
+		p.pos++
+		if t == nil {
+			return nil, p.errorf(start, "括弧の中が空です。")
+		}
+		return &Paren{Term: t}, nil
+	case '{':
+		if p.template {
+			return p.parseHole()
+		}
+	case '#':
+		if p.literals != nil {
+			return p.parseLiteral()
+		}
+	}
+
+	if p.spaced {
+		p.pos += len(p.matchIdent())
+		return &Atom{Name: p.src[start:p.pos]}, nil
+	}
+	if nm := p.matchName(); nm != "" {
+		p.pos += len(nm)
+		return &Atom{Name: nm}, nil
+	}
+	_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	return &Atom{Name: p.src[start:p.pos]}, nil
+}
+
+// parseHole は{0}や{x}のような引数の埋め込み位置を解析する。
+func (p *parser) parseHole() (Term, error) {
+	start := p.pos
+	end := strings.IndexByte(p.src[start:], '}')
+	if end < 0 {
+		return nil, p.errorf(start, "対応する閉じ波括弧がありません。")
+	}
+	s := p.src[start+1 : start+end]
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		i = p.paramIndex(s)
+		if i < 0 && 0 < len(p.params) {
+			return nil, p.errorf(start, "引数名%sは定義されていません。", s)
+		}
+	}
+	if i < 0 {
+		return nil, p.errorf(start, "引数の番号が不正です。番号=%s", s)
+	}
+	p.pos = start + end + 1
+	return &hole{Index: i}, nil
+}
+
+// parseLiteral は#5、#true、#falseのようなリテラルを解析し、項に展開する。
+func (p *parser) parseLiteral() (Term, error) {
+	start := p.pos
+	lit := p.matchLiteral()
+	body := lit[1:]
+
+	var (
+		t   Term
+		err error
+	)
+	switch body {
+	case "true", "false":
+		t, err = p.literals.Bool(body == "true", p.cs)
+	default:
+		n, aerr := strconv.Atoi(body)
+		if aerr != nil || n < 0 || strings.TrimLeft(body, "0123456789") != "" {
+			return nil, p.errorf(start, "リテラルが不正です。リテラル=%s", lit)
+		}
+		if MaxNumberLiteral < n {
+			return nil, p.errorf(start, "リテラル%sが大きすぎます。上限=%d", lit, MaxNumberLiteral)
+		}
+		t, err = p.literals.Number(n, p.cs)
+	}
+	if err != nil {
+		return nil, p.errorf(start, "リテラル%sを展開できません。%s", lit, err)
+	}
+	p.pos += len(lit)
+	return &Paren{Term: t}, nil
+}
+
+// matchLiteral は現在位置から始まるリテラルを返す。
+// 空白区切りの場合は識別子全体を、そうでない場合は#の後の数字の並びかtrueかfalseをリテラルとする。
+func (p *parser) matchLiteral() string {
+	if p.spaced {
+		return p.matchIdent()
+	}
+	s := p.src[p.pos+1:]
+	if n := len(s) - len(strings.TrimLeft(s, "0123456789")); 0 < n {
+		return "#" + s[:n]
+	}
+	for _, w := range []string{"true", "false"} {
+		if strings.HasPrefix(s, w) {
+			return "#" + w
+		}
+	}
+	return "#"
+}
+
+// paramIndex は引数名の位置を返す。引数名が定義されていない場合は-1を返す。
+func (p *parser) paramIndex(name string) int {
+	for i, prm := range p.params {
+		if prm == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchName は現在位置から始まる定義済みコンビネータの名前を返す。
+// 複数該当する場合は定義順に関係なく最も長いものを返す。
+func (p *parser) matchName() string {
+	return longestName(p.src[p.pos:], p.cs)
+}
+
+// longestName はsの先頭と一致する定義済みコンビネータの名前のうち、最も長いものを返す。
+// 一致するものがない場合は空文字列を返す。
+func longestName(s string, cs []Combinator) string {
+	var ret string
+	for _, c := range cs {
+		if len(ret) < len(c.Name) && strings.HasPrefix(s, c.Name) {
+			ret = c.Name
+		}
+	}
+	return ret
+}
+
+// PrefixWarnings はコンビネータ名が他のコンビネータ名の先頭と一致している組み合わせを、
+// 警告文として返す。
+// このような組み合わせでは、CLCodeの解析時に長い方の名前が優先される。
+func PrefixWarnings(cs []Combinator) []string {
+	var ws []string
+	for _, short := range cs {
+		for _, long := range cs {
+			if short.Name == "" || len(long.Name) <= len(short.Name) || !strings.HasPrefix(long.Name, short.Name) {
+				continue
+			}
+			w := fmt.Sprintf("コンビネータ名%sはコンビネータ名%sの先頭と一致しています。%sが優先されます。", short.Name, long.Name, long.Name)
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// matchIdent は現在位置から空白か括弧の手前までの識別子を返す。
+func (p *parser) matchIdent() string {
+	s := p.src[p.pos:]
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')'
+	})
+	if i < 0 {
+		return s
+	}
+	return s[:i]
+}
+
+// errorf は入力のバイト位置posの構文エラーを返す。
+func (p *parser) errorf(pos int, format string, a ...interface{}) *ParseError {
+	var (
+		before = p.src[:pos]
+		line   = strings.Count(before, "\n") + 1
+	)
+	if i := strings.LastIndexByte(before, '\n'); 0 <= i {
+		before = before[i+1:]
+	}
+	return &ParseError{
+		Line:   line,
+		Column: utf8.RuneCountInString(before) + 1,
+		Msg:    fmt.Sprintf(format, a...),
+	}
+}
+
+// skipSpace は空白文字を読み飛ばす。
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
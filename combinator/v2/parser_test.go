@@ -0,0 +1,230 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type TD struct {
+		clcode string
+		cs     []Combinator
+		expect Term
+		desc   string
+	}
+	var (
+		s = &Atom{Name: "S"}
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+		z = &Atom{Name: "z"}
+	)
+	tds := []TD{
+		TD{
+			clcode: "Sxyz",
+			cs:     cs,
+			expect: Apply(s, x, y, z),
+			desc:   "正常系",
+		},
+		TD{
+			clcode: "S(xy)z",
+			cs:     cs,
+			expect: Apply(s, &Paren{Term: Apply(x, y)}, z),
+			desc:   "括弧で括られた項は1つの引数",
+		},
+		TD{
+			clcode: " S x  y ",
+			cs:     cs,
+			expect: Apply(s, x, y),
+			desc:   "空白は読み飛ばす",
+		},
+		TD{
+			clcode: "<zero>x",
+			cs:     []Combinator{Combinator{Name: "<zero>"}},
+			expect: Apply(&Atom{Name: "<zero>"}, x),
+			desc:   "複数文字のコンビネータ",
+		},
+		TD{
+			clcode: "<zero>",
+			cs:     cs,
+			expect: Apply(&Atom{Name: "<"}, &Atom{Name: "z"}, &Atom{Name: "e"}, &Atom{Name: "r"}, &Atom{Name: "o"}, &Atom{Name: ">"}),
+			desc:   "未定義の文字は1文字ずつ別の項",
+		},
+	}
+	for _, td := range tds {
+		actual, err := Parse(td.clcode, td.cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc, td.clcode)
+	}
+
+	type ETD struct {
+		clcode string
+		expect *ParseError
+		desc   string
+	}
+	etds := []ETD{
+		ETD{
+			clcode: "S(Kx",
+			expect: &ParseError{Line: 1, Column: 2, Msg: "対応する閉じ括弧がありません。"},
+			desc:   "閉じ括弧がない",
+		},
+		ETD{
+			clcode: "Sx)y",
+			expect: &ParseError{Line: 1, Column: 3, Msg: "対応する開き括弧がありません。"},
+			desc:   "開き括弧がない",
+		},
+		ETD{
+			clcode: "S(x)()",
+			expect: &ParseError{Line: 1, Column: 5, Msg: "括弧の中が空です。"},
+			desc:   "括弧の中が空",
+		},
+		ETD{
+			clcode: "  ",
+			expect: &ParseError{Line: 1, Column: 3, Msg: "項が空です。"},
+			desc:   "空白だけ",
+		},
+		ETD{
+			clcode: "Sあ\nい)",
+			expect: &ParseError{Line: 2, Column: 2, Msg: "対応する開き括弧がありません。"},
+			desc:   "桁は文字単位で数える",
+		},
+	}
+	for _, td := range etds {
+		_, err := Parse(td.clcode, cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestParseErrorError(t *testing.T) {
+	err := &ParseError{Line: 1, Column: 2, Msg: "エラー"}
+	assert.Equal(t, "1:2: エラー", err.Error())
+	err.File = "in.list"
+	assert.Equal(t, "in.list:1:2: エラー", err.Error())
+}
+
+func TestParseLongestMatch(t *testing.T) {
+	var (
+		sb = &Atom{Name: "SB"}
+		s  = &Atom{Name: "S"}
+		x  = &Atom{Name: "x"}
+	)
+	short := Combinator{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"}
+	long := Combinator{Name: "SB", ArgsCount: 0, Format: "S(KS)K"}
+	for _, defs := range [][]Combinator{{short, long}, {long, short}} {
+		actual, err := Parse("SBxSx", defs)
+		assert.NoError(t, err)
+		assert.Equal(t, Apply(sb, x, s, x), actual, "定義順に関係なく最も長い名前に一致させる")
+	}
+}
+
+func TestPrefixWarnings(t *testing.T) {
+	defs := []Combinator{
+		Combinator{Name: "S"},
+		Combinator{Name: "SB"},
+		Combinator{Name: "K"},
+		Combinator{Name: "SBK"},
+	}
+	assert.Equal(t, []string{
+		"コンビネータ名Sはコンビネータ名SBの先頭と一致しています。SBが優先されます。",
+		"コンビネータ名Sはコンビネータ名SBKの先頭と一致しています。SBKが優先されます。",
+		"コンビネータ名SBはコンビネータ名SBKの先頭と一致しています。SBKが優先されます。",
+	}, PrefixWarnings(defs))
+	assert.Empty(t, PrefixWarnings(cs))
+}
+
+func TestParseSpaced(t *testing.T) {
+	var (
+		s   = &Atom{Name: "S"}
+		foo = &Atom{Name: "foo"}
+		bar = &Atom{Name: "bar"}
+		baz = &Atom{Name: "baz"}
+	)
+	type TD struct {
+		clcode string
+		expect Term
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			clcode: "S foo bar baz",
+			expect: Apply(s, foo, bar, baz),
+			desc:   "空白で区切る",
+		},
+		TD{
+			clcode: "S(foo bar)baz",
+			expect: Apply(s, &Paren{Term: Apply(foo, bar)}, baz),
+			desc:   "括弧で区切る",
+		},
+		TD{
+			clcode: "SKI",
+			expect: &Atom{Name: "SKI"},
+			desc:   "区切りがなければ1つの識別子",
+		},
+	}
+	for _, td := range tds {
+		actual, err := ParseSpaced(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc, td.clcode)
+	}
+
+	_, err := ParseSpaced("S (foo bar", cs)
+	assert.Equal(t, &ParseError{Line: 1, Column: 3, Msg: "対応する閉じ括弧がありません。"}, err)
+}
+
+func TestParserLiterals(t *testing.T) {
+	type TD struct {
+		clcode string
+		p      Parser
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "#2fx", p: Parser{Literals: ChurchEncoding}, expect: "S(S(KS)K)(S(S(KS)K)(KI))fx", desc: "数"},
+		TD{clcode: "#truexy", p: Parser{Literals: ChurchEncoding}, expect: "Kxy", desc: "真偽値"},
+		TD{clcode: "K#false#1 0", p: Parser{Literals: ScottEncoding}, expect: "K(KI)(K(SI(KK)))0", desc: "空白までを数とする"},
+		TD{clcode: "#1 f (#true x)", p: Parser{Spaced: true, Literals: ChurchEncoding}, expect: "S(S(KS)K)(KI)f(Kx)", desc: "空白区切り"},
+		TD{clcode: "#1", p: Parser{}, expect: "#1", desc: "リテラルを展開しない"},
+	}
+	for _, td := range tds {
+		actual, err := td.p.Parse(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc, td.clcode)
+	}
+
+	type TDE struct {
+		clcode string
+		p      Parser
+		cs     []Combinator
+		expect *ParseError
+		desc   string
+	}
+	tdes := []TDE{
+		TDE{clcode: "S#x", p: Parser{Literals: ChurchEncoding}, cs: cs, expect: &ParseError{Line: 1, Column: 2, Msg: "リテラルが不正です。リテラル=#"}, desc: "#の後に数も真偽値もない"},
+		TDE{clcode: "S #1x", p: Parser{Spaced: true, Literals: ChurchEncoding}, cs: cs, expect: &ParseError{Line: 1, Column: 3, Msg: "リテラルが不正です。リテラル=#1x"}, desc: "空白区切りで識別子全体がリテラルでない"},
+		TDE{clcode: "K#999999999", p: Parser{Literals: ChurchEncoding}, cs: cs, expect: &ParseError{Line: 1, Column: 2, Msg: "リテラル#999999999が大きすぎます。上限=10000"}, desc: "上限を超える自然数のリテラル"},
+		TDE{clcode: "#99999999999999999999", p: Parser{Literals: ScottEncoding}, cs: cs, expect: &ParseError{Line: 1, Column: 1, Msg: "リテラルが不正です。リテラル=#99999999999999999999"}, desc: "intに収まらない自然数のリテラル"},
+		TDE{clcode: "#1", p: Parser{Literals: ChurchEncoding}, cs: cs[1:], expect: &ParseError{Line: 1, Column: 1, Msg: "リテラル#1を展開できません。コンビネータS(引数3個)が定義されていません。"}, desc: "展開に必要なコンビネータがない"},
+	}
+	for _, td := range tdes {
+		_, err := td.p.Parse(td.clcode, td.cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := parseFormat("{0}{2}({1}{2})", nil, cs)
+	assert.NoError(t, err)
+	assert.Equal(t, Apply(&hole{Index: 0}, &hole{Index: 2}, &Paren{Term: Apply(&hole{Index: 1}, &hole{Index: 2})}), f)
+
+	f, err = parseFormat("{x}{z}({y}{2})", []string{"x", "y", "z"}, cs)
+	assert.NoError(t, err)
+	assert.Equal(t, Apply(&hole{Index: 0}, &hole{Index: 2}, &Paren{Term: Apply(&hole{Index: 1}, &hole{Index: 2})}), f, "引数名と番号を混ぜて使える")
+
+	for _, s := range []string{"{0", "{a}", "{-1}"} {
+		_, err := parseFormat(s, nil, cs)
+		assert.Error(t, err, s)
+	}
+
+	_, err = parseFormat("{x}{w}", []string{"x", "y"}, cs)
+	assert.Equal(t, &ParseError{Line: 1, Column: 4, Msg: "引数名wは定義されていません。"}, err)
+}
@@ -0,0 +1,58 @@
+package combinator
+
+import "strings"
+
+// Printer は項を文字列に変換する。
+// ゼロ値では、左結合の関数適用に必要な括弧だけを付けた正規の形で出力する。
+// 入力に書かれた冗長な括弧は出力しない。
+type Printer struct {
+	// FullParen がtrueの場合は全ての関数適用を括弧で括って出力する。
+	FullParen bool
+	// Spaced がtrueの場合は関数適用の間を空白で区切って出力する。
+	Spaced bool
+}
+
+// Canonical は左結合の関数適用に必要な括弧だけを付けて項を文字列に変換する。
+func Canonical(t Term) string {
+	return Printer{}.Print(t)
+}
+
+// Print は項を文字列に変換する。
+func (p Printer) Print(t Term) string {
+	var sb strings.Builder
+	p.write(&sb, t)
+	return sb.String()
+}
+
+// write は項を書き込む。
+func (p Printer) write(sb *strings.Builder, t Term) {
+	a, ok := unparen(t).(*App)
+	if !ok {
+		unparen(t).writeTo(sb)
+		return
+	}
+	if p.FullParen {
+		sb.WriteString("(")
+	}
+	p.write(sb, a.Fun)
+	if p.Spaced {
+		sb.WriteString(" ")
+	}
+	p.writeArg(sb, a.Arg)
+	if p.FullParen {
+		sb.WriteString(")")
+	}
+}
+
+// writeArg は関数適用の引数を書き込む。
+// 引数が関数適用の場合は括弧で括る。
+func (p Printer) writeArg(sb *strings.Builder, t Term) {
+	_, ok := unparen(t).(*App)
+	if !ok || p.FullParen {
+		p.write(sb, t)
+		return
+	}
+	sb.WriteString("(")
+	p.write(sb, t)
+	sb.WriteString(")")
+}
@@ -0,0 +1,73 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterPrint(t *testing.T) {
+	type TD struct {
+		clcode string
+		p      Printer
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			clcode: "SS((SS)S)",
+			p:      Printer{},
+			expect: "SS(SSS)",
+			desc:   "冗長な括弧は出力しない",
+		},
+		TD{
+			clcode: "((K))x",
+			p:      Printer{},
+			expect: "Kx",
+			desc:   "先頭の括弧は出力しない",
+		},
+		TD{
+			clcode: "xz(yz)",
+			p:      Printer{},
+			expect: "xz(yz)",
+			desc:   "必要な括弧は出力する",
+		},
+		TD{
+			clcode: "Sxyz",
+			p:      Printer{FullParen: true},
+			expect: "(((Sx)y)z)",
+			desc:   "全ての関数適用を括弧で括る",
+		},
+		TD{
+			clcode: "SS((SS)S)",
+			p:      Printer{Spaced: true},
+			expect: "S S (S S S)",
+			desc:   "空白で区切る",
+		},
+		TD{
+			clcode: "xz(yz)",
+			p:      Printer{FullParen: true, Spaced: true},
+			expect: "((x z) (y z))",
+			desc:   "全ての関数適用を括弧で括り、空白で区切る",
+		},
+		TD{
+			clcode: "(x)",
+			p:      Printer{FullParen: true},
+			expect: "x",
+			desc:   "Atomは括弧で括らない",
+		},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, td.p.Print(in), td.desc, td.clcode)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for _, s := range []string{"SS(SSS)", "x(y(zw))v", "S(K(SI))K"} {
+		in, err := Parse(s, cs)
+		assert.NoError(t, err)
+		assert.Equal(t, s, Canonical(in), "正規の形はそのまま出力する")
+	}
+}
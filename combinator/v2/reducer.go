@@ -0,0 +1,249 @@
+package combinator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Outcome は計算を終了した理由である。
+type Outcome int
+
+const (
+	// NormalForm は計算不可能になったため終了したことを表す。
+	NormalForm Outcome = iota
+	// StepLimit は指定のステップ数だけ計算したため終了したことを表す。
+	StepLimit
+	// CycleDetected は以前と同じ項が現れたため終了したことを表す。
+	CycleDetected
+	// Canceled はコンテキストがキャンセルされたため終了したことを表す。
+	Canceled
+	// TimedOut はコンテキストの期限を過ぎたため終了したことを表す。
+	TimedOut
+	// SizeLimit は項の大きさが上限を超えたため終了したことを表す。
+	SizeLimit
+	// DepthLimit は項の深さが上限を超えたため終了したことを表す。
+	DepthLimit
+)
+
+// Reducer は簡約戦略に従って項を計算する。
+type Reducer struct {
+	// Combinators はコンビネータ定義である。
+	Combinators []Combinator
+	// Strategy は簡約戦略である。nilの場合はWeakHeadで計算する。
+	Strategy Strategy
+	// MaxSteps は計算するステップ数の上限である。-1の場合は上限なしで計算する。
+	MaxSteps int
+	// MaxSize は項の大きさの上限である。0の場合は上限なしで計算する。
+	// 項の大きさはSizeで数える。
+	MaxSize int
+	// MaxDepth は項の深さの上限である。0の場合は上限なしで計算する。
+	// 項の深さはDepthで数える。
+	MaxDepth int
+	// DetectCycle がtrueの場合は以前と同じ項が現れた時点で計算を終了する。
+	// WeakHeadとNormalでは、恒等コンビネータの適用を取り除いて
+	// 以前と同じ項になった時点でも計算を終了する。
+	DetectCycle bool
+	// OnStep は1ステップ計算する毎に、計算したステップ数と計算結果を渡して呼ばれる。
+	OnStep func(step int, t Term)
+}
+
+// Result は計算結果である。
+type Result struct {
+	// Term は最後に計算した項である。
+	Term Term
+	// Steps は計算したステップ数である。
+	Steps int
+	// Outcome は計算を終了した理由である。
+	Outcome Outcome
+	// Cycle は循環を検出した場合の循環の情報である。
+	Cycle *Cycle
+}
+
+// Cycle は計算中に検出した循環である。
+type Cycle struct {
+	// Period は循環の周期である。
+	Period int `json:"period"`
+	// Step は循環を検出したステップ数である。
+	Step int `json:"step"`
+	// IgnoringIdentities は恒等コンビネータの適用を取り除いた項で循環を検出したことを表す。
+	// この場合のPeriodは取り除いた項の周期で、計算した項そのものは毎回異なる。
+	IgnoringIdentities bool `json:"ignoringIdentities,omitempty"`
+}
+
+// Message は計算を途中で打ち切った理由を返す。
+// 打ち切っていない場合は空文字列を返す。
+func (r Result) Message() string {
+	switch r.Outcome {
+	case CycleDetected:
+		if r.Cycle.IgnoringIdentities {
+			return fmt.Sprintf("cycle of period %d ignoring identity applications detected at step %d", r.Cycle.Period, r.Cycle.Step)
+		}
+		return fmt.Sprintf("cycle of period %d detected at step %d", r.Cycle.Period, r.Cycle.Step)
+	case Canceled:
+		return fmt.Sprintf("canceled at step %d", r.Steps)
+	case TimedOut:
+		return fmt.Sprintf("timed out at step %d", r.Steps)
+	case SizeLimit:
+		return fmt.Sprintf("size limit exceeded at step %d", r.Steps)
+	case DepthLimit:
+		return fmt.Sprintf("depth limit exceeded at step %d", r.Steps)
+	}
+	return ""
+}
+
+// Reduce は項を計算不可能になるか、計算を打ち切るまで計算する。
+func (r Reducer) Reduce(t Term) Result {
+	return r.ReduceContext(context.Background(), t)
+}
+
+// ReduceContext は項を計算不可能になるか、計算を打ち切るまで計算する。
+// コンテキストがキャンセルされるか期限を過ぎた場合は、その時点の項を返す。
+func (r Reducer) ReduceContext(ctx context.Context, t Term) Result {
+	st := r.Strategy
+	if st == nil {
+		st = WeakHead
+	}
+
+	var seen history
+	if r.DetectCycle {
+		seen = history{}
+		seen.add(t, 0)
+	}
+
+	// SII(SII)のように計算するたびにIの適用が増えていく項は、同じ項が現れないため
+	// 恒等コンビネータの適用を取り除いた項で循環を調べる。
+	// 恒等コンビネータの計算では取り除いた項が変わらず適用が1つ減るため、その計算の後は調べない。
+	var (
+		ids   map[string]bool
+		loose history
+		prev  Term
+		nids  int
+	)
+	if r.DetectCycle && (st == WeakHead || st == Normal) {
+		ids = identities(r.Combinators)
+	}
+	if 0 < len(ids) {
+		loose = history{}
+		prev, nids = stripIdentities(t, ids)
+		loose.add(prev, 0)
+	}
+
+	for step := 0; ; {
+		if r.MaxSteps >= 0 && r.MaxSteps <= step {
+			return Result{Term: t, Steps: step, Outcome: StepLimit}
+		}
+		switch ctx.Err() {
+		case context.Canceled:
+			return Result{Term: t, Steps: step, Outcome: Canceled}
+		case context.DeadlineExceeded:
+			return Result{Term: t, Steps: step, Outcome: TimedOut}
+		}
+		next, ok := st.Step(t, r.Combinators)
+		if !ok {
+			return Result{Term: t, Steps: step, Outcome: NormalForm}
+		}
+		t = next
+		step++
+		if r.OnStep != nil {
+			r.OnStep(step, t)
+		}
+		if o, ok := r.exceeds(t); ok {
+			return Result{Term: t, Steps: step, Outcome: o}
+		}
+		if seen == nil {
+			continue
+		}
+		if prev, ok := seen.find(t); ok {
+			c := &Cycle{Period: step - prev, Step: step}
+			return Result{Term: t, Steps: step, Outcome: CycleDetected, Cycle: c}
+		}
+		seen.add(t, step)
+		if loose == nil {
+			continue
+		}
+		stripped, n := stripIdentities(t, ids)
+		idStep := n == nids-1 && Equal(stripped, prev)
+		prev, nids = stripped, n
+		if idStep {
+			continue
+		}
+		if p, ok := loose.find(stripped); ok {
+			c := &Cycle{Period: step - p, Step: step, IgnoringIdentities: true}
+			return Result{Term: t, Steps: step, Outcome: CycleDetected, Cycle: c}
+		}
+		loose.add(stripped, step)
+	}
+}
+
+// identities は恒等コンビネータ(引数1個で引数をそのまま返すコンビネータ)の名前を返す。
+func identities(cs []Combinator) map[string]bool {
+	ids := map[string]bool{}
+	for _, c := range cs {
+		if co, _ := findCombinator(c.Name, cs); c.ArgsCount != 1 || co.Format != c.Format {
+			continue
+		}
+		f, err := parseFormat(c.Format, c.Params, cs)
+		if err != nil {
+			continue
+		}
+		if h, ok := unparen(f).(*hole); ok && h.Index == 0 {
+			ids[c.Name] = true
+		}
+	}
+	return ids
+}
+
+// stripIdentities は項の中にある恒等コンビネータの適用を取り除いた項と、取り除いた適用の数を返す。
+func stripIdentities(t Term, ids map[string]bool) (Term, int) {
+	head, args := Spine(t)
+	if a, ok := head.(*Atom); ok && ids[a.Name] && 0 < len(args) {
+		ret, n := stripIdentities(Apply(args[0], args[1:]...), ids)
+		return ret, n + 1
+	}
+	var (
+		ret = make([]Term, len(args))
+		n   int
+	)
+	for i, a := range args {
+		var m int
+		ret[i], m = stripIdentities(a, ids)
+		n += m
+	}
+	return Apply(head, ret...), n
+}
+
+// exceeds は項の大きさか深さが上限を超えている場合に、計算を終了する理由を返す。
+func (r Reducer) exceeds(t Term) (Outcome, bool) {
+	if 0 < r.MaxSize && r.MaxSize < sizeUpTo(t, r.MaxSize+1) {
+		return SizeLimit, true
+	}
+	if 0 < r.MaxDepth && r.MaxDepth < depthUpTo(t, r.MaxDepth+1) {
+		return DepthLimit, true
+	}
+	return NormalForm, false
+}
+
+// history は計算中に現れた項を、項のハッシュ値で記録する。
+type history map[uint64][]seenTerm
+
+// seenTerm は計算中に現れた項と、その項が現れたステップ数である。
+type seenTerm struct {
+	term Term
+	step int
+}
+
+// add は項を記録する。
+func (h history) add(t Term, step int) {
+	k := Hash(t)
+	h[k] = append(h[k], seenTerm{term: t, step: step})
+}
+
+// find は記録済みの項であれば、その項が現れたステップ数を返す。
+func (h history) find(t Term) (int, bool) {
+	for _, s := range h[Hash(t)] {
+		if Equal(s.term, t) {
+			return s.step, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,207 @@
+package combinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReducerReduce(t *testing.T) {
+	m := append([]Combinator{Combinator{Name: "M", ArgsCount: 1, Format: "{0}{0}"}}, cs...)
+	type TD struct {
+		clcode  string
+		r       Reducer
+		expect  string
+		steps   int
+		outcome Outcome
+		cycle   *Cycle
+		desc    string
+	}
+	tds := []TD{
+		TD{
+			clcode:  "SKIx",
+			r:       Reducer{Combinators: cs, MaxSteps: -1, DetectCycle: true},
+			expect:  "x",
+			steps:   2,
+			outcome: NormalForm,
+			desc:    "計算不可能になるまで計算する",
+		},
+		TD{
+			clcode:  "SKIx",
+			r:       Reducer{Combinators: cs, MaxSteps: 1},
+			expect:  "Kx(Ix)",
+			steps:   1,
+			outcome: StepLimit,
+			desc:    "ステップ数の上限で終了する",
+		},
+		TD{
+			clcode:  "MM",
+			r:       Reducer{Combinators: m, MaxSteps: -1, DetectCycle: true},
+			expect:  "MM",
+			steps:   1,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 1, Step: 1},
+			desc:    "同じ項が続く循環を検出する",
+		},
+		TD{
+			clcode:  "SII(SII)",
+			r:       Reducer{Combinators: cs, Strategy: Applicative, MaxSteps: -1, DetectCycle: true},
+			expect:  "SII(SII)",
+			steps:   3,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 3, Step: 3},
+			desc:    "周期のある循環を検出する",
+		},
+		TD{
+			clcode:  "SII(SII)",
+			r:       Reducer{Combinators: cs, MaxSteps: -1, DetectCycle: true},
+			expect:  "I(SII)(I(SII))",
+			steps:   1,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 1, Step: 1, IgnoringIdentities: true},
+			desc:    "引数にIの適用が増えていく循環を検出する",
+		},
+		TD{
+			clcode:  "SII(SII)",
+			r:       Reducer{Combinators: cs, Strategy: Normal, MaxSteps: -1, DetectCycle: true},
+			expect:  "I(SII)(I(SII))",
+			steps:   1,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 1, Step: 1, IgnoringIdentities: true},
+			desc:    "正規形まで計算する場合も引数にIの適用が増えていく循環を検出する",
+		},
+		TD{
+			clcode:  "x(SII(SII))",
+			r:       Reducer{Combinators: cs, Strategy: Normal, MaxSteps: -1, DetectCycle: true},
+			expect:  "x(I(SII)(I(SII)))",
+			steps:   1,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 1, Step: 1, IgnoringIdentities: true},
+			desc:    "引数の中でIの適用が増えていく循環を検出する",
+		},
+		TD{
+			clcode:  "K(SII(SII))",
+			r:       Reducer{Combinators: cs, Strategy: Normal, MaxSteps: -1, DetectCycle: true},
+			expect:  "K(I(SII)(I(SII)))",
+			steps:   1,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 1, Step: 1, IgnoringIdentities: true},
+			desc:    "引数の足りないコンビネータの引数の中の循環を検出する",
+		},
+		TD{
+			clcode:  "K(SII(SII))",
+			r:       Reducer{Combinators: cs, Strategy: Applicative, MaxSteps: -1, DetectCycle: true},
+			expect:  "K(SII(SII))",
+			steps:   3,
+			outcome: CycleDetected,
+			cycle:   &Cycle{Period: 3, Step: 3},
+			desc:    "最左最内から計算する場合は引数の中の循環を同じ項で検出する",
+		},
+		TD{
+			clcode:  "I(Ix)y",
+			r:       Reducer{Combinators: cs, MaxSteps: -1, DetectCycle: true},
+			expect:  "xy",
+			steps:   2,
+			outcome: NormalForm,
+			desc:    "先頭のIの計算は循環として扱わない",
+		},
+		TD{
+			clcode:  "K(I(Ix))",
+			r:       Reducer{Combinators: cs, Strategy: Normal, MaxSteps: -1, DetectCycle: true},
+			expect:  "Kx",
+			steps:   2,
+			outcome: NormalForm,
+			desc:    "引数の中のIの計算は循環として扱わない",
+		},
+		TD{
+			clcode:  "x(MM)",
+			r:       Reducer{Combinators: m, Strategy: Normal, MaxSteps: 10},
+			expect:  "x(MM)",
+			steps:   10,
+			outcome: StepLimit,
+			desc:    "循環を検出しない",
+		},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, td.r.Combinators)
+		assert.NoError(t, err, td.desc)
+		ret := td.r.Reduce(in)
+		assert.Equal(t, td.expect, ret.Term.String(), td.desc)
+		assert.Equal(t, td.steps, ret.Steps, td.desc)
+		assert.Equal(t, td.outcome, ret.Outcome, td.desc)
+		assert.Equal(t, td.cycle, ret.Cycle, td.desc)
+	}
+}
+
+func TestReducerOnStep(t *testing.T) {
+	var process []string
+	r := Reducer{
+		Combinators: cs,
+		MaxSteps:    -1,
+		OnStep: func(step int, t Term) {
+			process = append(process, t.String())
+		},
+	}
+	in, err := Parse("SKIx", cs)
+	assert.NoError(t, err)
+	r.Reduce(in)
+	assert.Equal(t, []string{"Kx(Ix)", "x"}, process)
+}
+
+func TestResultMessage(t *testing.T) {
+	assert.Equal(t, "", Result{Outcome: NormalForm}.Message())
+	assert.Equal(t, "", Result{Outcome: StepLimit}.Message())
+	assert.Equal(t, "cycle of period 3 detected at step 5", Result{Outcome: CycleDetected, Cycle: &Cycle{Period: 3, Step: 5}}.Message())
+	assert.Equal(t, "cycle of period 1 ignoring identity applications detected at step 2", Result{Outcome: CycleDetected, Cycle: &Cycle{Period: 1, Step: 2, IgnoringIdentities: true}}.Message())
+}
+
+func TestReducerReduceContext(t *testing.T) {
+	// 計算が終わらない項でも、コンテキストで計算を打ち切る
+	// IのかわりにSKKを使うと恒等コンビネータの適用を取り除いても循環を検出できない
+	in, err := Parse("S(SKK)(SKK)(S(SKK)(SKK))", cs)
+	assert.NoError(t, err)
+	r := Reducer{Combinators: cs, MaxSteps: -1, DetectCycle: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.OnStep = func(step int, t Term) {
+		if step == 5 {
+			cancel()
+		}
+	}
+	ret := r.ReduceContext(ctx, in)
+	assert.Equal(t, Canceled, ret.Outcome)
+	assert.Equal(t, 5, ret.Steps)
+	assert.Equal(t, "canceled at step 5", ret.Message())
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r.OnStep = nil
+	ret = r.ReduceContext(ctx, in)
+	assert.Equal(t, TimedOut, ret.Outcome)
+	assert.NotNil(t, ret.Term, "途中の計算結果を返す")
+}
+
+func TestReducerLimit(t *testing.T) {
+	in, err := Parse("SII(SII)", cs)
+	assert.NoError(t, err)
+
+	r := Reducer{Combinators: cs, MaxSteps: -1, MaxSize: 10}
+	ret := r.Reduce(in)
+	assert.Equal(t, SizeLimit, ret.Outcome)
+	assert.True(t, 10 < Size(ret.Term))
+	assert.Equal(t, "size limit exceeded at step 6", ret.Message())
+
+	r = Reducer{Combinators: cs, MaxSteps: -1, MaxDepth: 5}
+	ret = r.Reduce(in)
+	assert.Equal(t, DepthLimit, ret.Outcome)
+	assert.True(t, 5 < Depth(ret.Term))
+	assert.Equal(t, "depth limit exceeded at step 3", ret.Message())
+
+	r = Reducer{Combinators: cs, MaxSteps: -1, MaxSize: 4, MaxDepth: 3}
+	in, err = Parse("SKIx", cs)
+	assert.NoError(t, err)
+	ret = r.Reduce(in)
+	assert.Equal(t, NormalForm, ret.Outcome, "上限を超えなければ最後まで計算する")
+}
@@ -0,0 +1,165 @@
+package combinator
+
+// Strategy は簡約戦略である。
+// 項の中のどのコンビネータから計算するかを決める。
+type Strategy interface {
+	// Step は項を一度だけ計算する。
+	// 計算できなかった場合は元の項とfalseを返す。
+	Step(t Term, cs []Combinator) (Term, bool)
+}
+
+var (
+	// WeakHead は先頭のコンビネータだけを計算する。
+	// 先頭が計算できなくなった時点で計算を終了する。
+	WeakHead Strategy = weakHead{}
+	// Normal は最左最外のコンビネータから計算する。
+	// 先頭が計算できなくなったら引数の中を左から順に計算し、正規形まで計算する。
+	Normal Strategy = normalOrder{}
+	// Applicative は最左最内のコンビネータから計算する。
+	// コンビネータは引数をすべて計算してから計算する。
+	Applicative Strategy = applicativeOrder{}
+	// ParallelOutermost は最外のコンビネータをすべて同時に計算する。
+	ParallelOutermost Strategy = parallelOutermost{}
+)
+
+// Strategies は簡約戦略の名前と簡約戦略の対応である。
+var Strategies = map[string]Strategy{
+	"weakhead":    WeakHead,
+	"normal":      Normal,
+	"applicative": Applicative,
+	"parallel":    ParallelOutermost,
+}
+
+type (
+	weakHead          struct{}
+	normalOrder       struct{}
+	applicativeOrder  struct{}
+	parallelOutermost struct{}
+)
+
+func (weakHead) Step(t Term, cs []Combinator) (Term, bool) {
+	return Step(t, cs)
+}
+
+func (normalOrder) Step(t Term, cs []Combinator) (Term, bool) {
+	return stepNormal(t, cs)
+}
+
+func (applicativeOrder) Step(t Term, cs []Combinator) (Term, bool) {
+	return stepApplicative(t, cs)
+}
+
+func (parallelOutermost) Step(t Term, cs []Combinator) (Term, bool) {
+	return stepParallelOutermost(t, cs)
+}
+
+// stepNormal は最左最外のコンビネータを一度だけ計算する。
+// 先頭が計算できない場合は引数の中を左から順に探して計算する。
+func stepNormal(t Term, cs []Combinator) (Term, bool) {
+	if ret, ok := Step(t, cs); ok {
+		return ret, true
+	}
+	head, args := Spine(t)
+	if ret, ok := stepArgs(args, stepNormal, cs); ok {
+		return Apply(head, ret...), true
+	}
+	return t, false
+}
+
+// stepApplicative は最左最内のコンビネータを一度だけ計算する。
+// 先頭のコンビネータの引数に計算できるものがあれば先に計算する。
+func stepApplicative(t Term, cs []Combinator) (Term, bool) {
+	head, args := Spine(t)
+	n := len(args)
+	if co, ok := headCombinator(head, len(args), cs); ok {
+		n = co.ArgsCount
+	}
+	if ret, ok := stepArgs(args[:n], stepApplicative, cs); ok {
+		return Apply(head, append(ret, args[n:]...)...), true
+	}
+	if ret, ok := Step(t, cs); ok {
+		return ret, true
+	}
+	if ret, ok := stepArgs(args[n:], stepApplicative, cs); ok {
+		return Apply(head, append(args[:n:n], ret...)...), true
+	}
+	return t, false
+}
+
+// stepParallelOutermost は最外のコンビネータをすべて同時に一度ずつ計算する。
+// 先頭のコンビネータを計算した場合も、その引数以外の引数の中は計算する。
+func stepParallelOutermost(t Term, cs []Combinator) (Term, bool) {
+	head, args := Spine(t)
+	co, ok := headCombinator(head, len(args), cs)
+	if !ok {
+		ret, changed := mapArgs(args, stepParallelOutermost, cs)
+		if !changed {
+			return t, false
+		}
+		return Apply(head, ret...), true
+	}
+	body, err := co.instantiate(args[:co.ArgsCount], cs)
+	if err != nil {
+		return t, false
+	}
+	rest, _ := mapArgs(args[co.ArgsCount:], stepParallelOutermost, cs)
+	return Apply(body, rest...), true
+}
+
+// headCombinator は先頭の項が引数の数だけ引数を受け取れる定義済みコンビネータであれば、
+// そのコンビネータ定義を返す。
+func headCombinator(head Term, argc int, cs []Combinator) (Combinator, bool) {
+	a, ok := head.(*Atom)
+	if !ok {
+		return Combinator{}, false
+	}
+	co, ok := findCombinator(a.Name, cs)
+	if !ok || argc < co.ArgsCount {
+		return Combinator{}, false
+	}
+	return co, true
+}
+
+// stepArgs は引数を左から順に計算し、最初に計算できた引数だけを置き換えた引数を返す。
+// どの引数も計算できなかった場合はfalseを返す。
+func stepArgs(args []Term, step func(Term, []Combinator) (Term, bool), cs []Combinator) ([]Term, bool) {
+	for i, a := range args {
+		ret, ok := step(unparen(a), cs)
+		if !ok {
+			continue
+		}
+		newArgs := make([]Term, len(args))
+		copy(newArgs, args)
+		newArgs[i] = wrapArg(ret)
+		return newArgs, true
+	}
+	return nil, false
+}
+
+// mapArgs はすべての引数をそれぞれ計算した引数を返す。
+// 1つでも計算できた引数があればtrueを返す。
+func mapArgs(args []Term, step func(Term, []Combinator) (Term, bool), cs []Combinator) ([]Term, bool) {
+	var (
+		newArgs = make([]Term, len(args))
+		changed bool
+	)
+	for i, a := range args {
+		ret, ok := step(unparen(a), cs)
+		if !ok {
+			newArgs[i] = a
+			continue
+		}
+		newArgs[i] = wrapArg(ret)
+		changed = true
+	}
+	return newArgs, changed
+}
+
+// wrapArg は引数に置く項を必要な場合だけ括弧で括る。
+func wrapArg(t Term) Term {
+	t = unparen(t)
+	if _, ok := t.(*App); ok {
+		return &Paren{Term: t}
+	}
+	return t
+}
@@ -0,0 +1,122 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategies(t *testing.T) {
+	type TD struct {
+		clcode string
+		st     Strategy
+		n      int
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			clcode: "K(Ix)",
+			st:     WeakHead,
+			n:      -1,
+			expect: "K(Ix)",
+			desc:   "WeakHead:引数の中は計算しない",
+		},
+		TD{
+			clcode: "K(Ix)",
+			st:     Normal,
+			n:      -1,
+			expect: "Kx",
+			desc:   "Normal:先頭が計算できなくなったら引数の中を計算する",
+		},
+		TD{
+			clcode: "x(Iy)(Iz)",
+			st:     Normal,
+			n:      1,
+			expect: "xy(Iz)",
+			desc:   "Normal:引数は左から順に計算する",
+		},
+		TD{
+			clcode: "x(K(Sxy)z(I(Iw)))",
+			st:     Normal,
+			n:      -1,
+			expect: "x(xw(yw))",
+			desc:   "Normal:ネストした引数の中も計算する",
+		},
+		TD{
+			clcode: "K(Ia)(Ib)",
+			st:     Normal,
+			n:      1,
+			expect: "Ia",
+			desc:   "Normal:最外のコンビネータから計算する",
+		},
+		TD{
+			clcode: "K(Ia)(Ib)",
+			st:     Applicative,
+			n:      1,
+			expect: "Ka(Ib)",
+			desc:   "Applicative:引数から計算する",
+		},
+		TD{
+			clcode: "K(Ia)(Ib)",
+			st:     Applicative,
+			n:      2,
+			expect: "Kab",
+			desc:   "Applicative:引数をすべて計算してからコンビネータを計算する",
+		},
+		TD{
+			clcode: "K(Ia)(Ib)",
+			st:     Applicative,
+			n:      -1,
+			expect: "a",
+			desc:   "Applicative:最後まで計算する",
+		},
+		TD{
+			clcode: "Ix(Iy)",
+			st:     Applicative,
+			n:      1,
+			expect: "x(Iy)",
+			desc:   "Applicative:コンビネータが受け取らない引数は後で計算する",
+		},
+		TD{
+			clcode: "x(Ia)(Ib)",
+			st:     ParallelOutermost,
+			n:      1,
+			expect: "xab",
+			desc:   "ParallelOutermost:最外のコンビネータをすべて計算する",
+		},
+		TD{
+			clcode: "I(Ia)(Ib)",
+			st:     ParallelOutermost,
+			n:      1,
+			expect: "Iab",
+			desc:   "ParallelOutermost:計算したコンビネータの引数の中は計算しない",
+		},
+	}
+	for _, td := range tds {
+		actual, err := CalcCLCode(td.clcode, cs, td.n, td.st)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc, td.clcode)
+	}
+}
+
+func TestStrategiesTermination(t *testing.T) {
+	// 同じ項でも簡約戦略によって停止したりしなかったりする
+	for _, st := range []Strategy{WeakHead, Normal, ParallelOutermost} {
+		actual, err := CalcCLCode("KI(SII(SII))", cs, 100, st)
+		assert.NoError(t, err)
+		assert.Equal(t, "I", actual, st)
+	}
+
+	in, err := Parse("KI(SII(SII))", cs)
+	assert.NoError(t, err)
+	out, ok := Applicative.Step(Reduce(in, cs, 100, Applicative), cs)
+	assert.True(t, ok, "Applicativeでは計算が終わらない")
+	assert.Contains(t, out.String(), "SII")
+}
+
+func TestStrategiesName(t *testing.T) {
+	for _, nm := range []string{"weakhead", "normal", "applicative", "parallel"} {
+		assert.NotNil(t, Strategies[nm], nm)
+	}
+}
@@ -0,0 +1,224 @@
+package combinator
+
+import (
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Term はコンビネータ論理の項である。
+// 項は不変であり、簡約時は変更のあった部分だけを作り直す。
+// Stringは入力やコンビネータ定義に書かれた括弧をそのまま残して文字列に変換する。
+// 正規の形で文字列に変換する場合はPrinterを使う。
+type Term interface {
+	String() string
+	writeTo(sb *strings.Builder)
+}
+
+// Atom はそれ以上分割できない項である。
+// 定義済みコンビネータか、未定義の変数のいずれかを表す。
+type Atom struct {
+	Name string
+}
+
+// App は関数適用を表す項である。
+// FunにArgを適用する。
+type App struct {
+	Fun Term
+	Arg Term
+}
+
+// Paren は括弧で括られた項である。
+// 入力やコンビネータ定義に書かれた括弧をそのまま保持する。
+type Paren struct {
+	Term Term
+}
+
+// hole はコンビネータ定義のFormat中の{0}などの引数の埋め込み位置である。
+type hole struct {
+	Index int
+}
+
+func (a *Atom) String() string  { return termString(a) }
+func (a *App) String() string   { return termString(a) }
+func (p *Paren) String() string { return termString(p) }
+func (h *hole) String() string  { return termString(h) }
+
+func (a *Atom) writeTo(sb *strings.Builder) {
+	sb.WriteString(a.Name)
+}
+
+func (a *App) writeTo(sb *strings.Builder) {
+	a.Fun.writeTo(sb)
+	// 括弧のない関数適用が引数にある場合は括弧で括らないと意味が変わる
+	if _, ok := a.Arg.(*App); ok {
+		sb.WriteString("(")
+		a.Arg.writeTo(sb)
+		sb.WriteString(")")
+		return
+	}
+	a.Arg.writeTo(sb)
+}
+
+func (p *Paren) writeTo(sb *strings.Builder) {
+	sb.WriteString("(")
+	p.Term.writeTo(sb)
+	sb.WriteString(")")
+}
+
+func (h *hole) writeTo(sb *strings.Builder) {
+	sb.WriteString("{")
+	sb.WriteString(strconv.Itoa(h.Index))
+	sb.WriteString("}")
+}
+
+// termString は項を文字列に変換する。
+func termString(t Term) string {
+	var sb strings.Builder
+	t.writeTo(&sb)
+	return sb.String()
+}
+
+// Apply は項に引数を左から順に適用した項を返す。
+func Apply(t Term, args ...Term) Term {
+	for _, a := range args {
+		t = &App{Fun: t, Arg: a}
+	}
+	return t
+}
+
+// Spine は項を先頭の項と引数に分解する。
+// 先頭にある括弧は展開してから分解する。
+func Spine(t Term) (Term, []Term) {
+	var rev []Term
+	for {
+		switch v := t.(type) {
+		case *App:
+			rev = append(rev, v.Arg)
+			t = v.Fun
+		case *Paren:
+			t = v.Term
+		default:
+			args := make([]Term, len(rev))
+			for i, a := range rev {
+				args[len(rev)-1-i] = a
+			}
+			return t, args
+		}
+	}
+}
+
+// unparen は外側の括弧をすべて外した項を返す。
+func unparen(t Term) Term {
+	for {
+		p, ok := t.(*Paren)
+		if !ok {
+			return t
+		}
+		t = p.Term
+	}
+}
+
+// Equal は2つの項が同じ構造であるかを返す。
+// 括弧の有無は区別しない。
+func Equal(a, b Term) bool {
+	a, b = unparen(a), unparen(b)
+	switch x := a.(type) {
+	case *Atom:
+		y, ok := b.(*Atom)
+		return ok && x.Name == y.Name
+	case *App:
+		y, ok := b.(*App)
+		return ok && Equal(x.Fun, y.Fun) && Equal(x.Arg, y.Arg)
+	case *hole:
+		y, ok := b.(*hole)
+		return ok && x.Index == y.Index
+	}
+	return false
+}
+
+// Size は項に含まれるAtomの数を返す。
+func Size(t Term) int {
+	return sizeUpTo(t, -1)
+}
+
+// sizeUpTo は項に含まれるAtomの数を返す。
+// 数えている途中でlimitに達した場合はその時点でlimitを返す。limitが-1の場合は最後まで数える。
+func sizeUpTo(t Term, limit int) int {
+	var (
+		n     int
+		stack = []Term{t}
+	)
+	for 0 < len(stack) {
+		if n == limit {
+			return n
+		}
+		t, stack = unparen(stack[len(stack)-1]), stack[:len(stack)-1]
+		if a, ok := t.(*App); ok {
+			stack = append(stack, a.Arg, a.Fun)
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// Depth は括弧を除いた関数適用の木の深さを返す。
+// Atomの深さは1である。
+func Depth(t Term) int {
+	return depthUpTo(t, -1)
+}
+
+// depthUpTo は括弧を除いた関数適用の木の深さを返す。
+// 深さがlimitに達した場合はその時点でlimitを返す。limitが-1の場合は最後まで数える。
+func depthUpTo(t Term, limit int) int {
+	type item struct {
+		t Term
+		d int
+	}
+	var (
+		max   int
+		stack = []item{{t: t, d: 1}}
+	)
+	for 0 < len(stack) {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if max < it.d {
+			max = it.d
+		}
+		if max == limit {
+			return max
+		}
+		if a, ok := unparen(it.t).(*App); ok {
+			stack = append(stack, item{t: a.Arg, d: it.d + 1}, item{t: a.Fun, d: it.d + 1})
+		}
+	}
+	return max
+}
+
+// Hash は項のハッシュ値を返す。
+// Equalで等しい項は同じハッシュ値になる。
+func Hash(t Term) uint64 {
+	h := fnv.New64a()
+	writeHash(h, t)
+	return h.Sum64()
+}
+
+// writeHash は括弧を除いた項の構造を書き込む。
+func writeHash(w io.Writer, t Term) {
+	switch v := unparen(t).(type) {
+	case *Atom:
+		io.WriteString(w, "a")
+		io.WriteString(w, v.Name)
+		io.WriteString(w, "\x00")
+	case *App:
+		io.WriteString(w, "@")
+		writeHash(w, v.Fun)
+		writeHash(w, v.Arg)
+	case *hole:
+		io.WriteString(w, "h")
+		io.WriteString(w, strconv.Itoa(v.Index))
+		io.WriteString(w, "\x00")
+	}
+}
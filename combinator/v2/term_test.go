@@ -0,0 +1,69 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermString(t *testing.T) {
+	var (
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+		z = &Atom{Name: "z"}
+	)
+	assert.Equal(t, "xyz", Apply(x, y, z).String())
+	assert.Equal(t, "x(yz)", Apply(x, Apply(y, z)).String(), "引数の関数適用は括弧で括る")
+	assert.Equal(t, "((x))y", Apply(&Paren{Term: &Paren{Term: x}}, y).String(), "書かれた括弧はそのまま出力する")
+}
+
+func TestSpine(t *testing.T) {
+	var (
+		s = &Atom{Name: "S"}
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+	)
+	head, args := Spine(Apply(&Paren{Term: Apply(s, x)}, y))
+	assert.Equal(t, s, head)
+	assert.Equal(t, []Term{x, y}, args, "先頭の括弧は展開する")
+
+	head, args = Spine(x)
+	assert.Equal(t, x, head)
+	assert.Empty(t, args)
+}
+
+func TestEqual(t *testing.T) {
+	var (
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+	)
+	assert.True(t, Equal(Apply(x, y), Apply(&Atom{Name: "x"}, &Atom{Name: "y"})))
+	assert.True(t, Equal(Apply(x, y), &Paren{Term: Apply(x, y)}), "括弧の有無は区別しない")
+	assert.False(t, Equal(Apply(x, y), Apply(y, x)))
+}
+
+func TestHash(t *testing.T) {
+	var (
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+	)
+	assert.Equal(t, Hash(Apply(x, y)), Hash(&Paren{Term: Apply(&Atom{Name: "x"}, y)}), "括弧の有無は区別しない")
+	assert.NotEqual(t, Hash(Apply(x, y)), Hash(Apply(y, x)))
+	assert.NotEqual(t, Hash(Apply(x, Apply(y, y))), Hash(Apply(x, y, y)))
+}
+
+func TestSizeAndDepth(t *testing.T) {
+	var (
+		x = &Atom{Name: "x"}
+		y = &Atom{Name: "y"}
+		z = &Atom{Name: "z"}
+	)
+	assert.Equal(t, 1, Size(x))
+	assert.Equal(t, 3, Size(Apply(x, &Paren{Term: Apply(y, z)})))
+	assert.Equal(t, 2, sizeUpTo(Apply(x, y, z), 2), "上限に達したら数えるのをやめる")
+
+	assert.Equal(t, 1, Depth(x))
+	assert.Equal(t, 3, Depth(Apply(x, y, z)))
+	assert.Equal(t, 3, Depth(Apply(x, &Paren{Term: Apply(y, z)})), "括弧は深さに数えない")
+	assert.Equal(t, 2, depthUpTo(Apply(x, y, z), 2), "上限に達したら数えるのをやめる")
+}
@@ -0,0 +1,124 @@
+package combinator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// unlambdaNames はUnlambdaの組み込み関数と、対応するコンビネータ定義の名前と引数の数である。
+var unlambdaNames = map[byte]struct {
+	name string
+	argc int
+}{
+	's': {name: "S", argc: 3},
+	'k': {name: "K", argc: 2},
+	'i': {name: "I", argc: 1},
+}
+
+// ParseUnlambda はUnlambdaの書式のCLCodeを解析して項を返す。
+// 「`」は続く2つの項の関数適用で、「```skk」はSKKになる。
+// s、k、iは定義済みのS、K、Iとして解析し、それ以外はParseと同じく定義済みコンビネータの名前か1文字を1つの項とする。
+// 空白と、「#」から行末までのコメントは読み飛ばす。
+// 解析できない場合は*ParseErrorを返す。
+func ParseUnlambda(clcode string, cs []Combinator) (Term, error) {
+	p := &parser{src: clcode, cs: cs}
+	p.skipUnlambdaSpace()
+	if len(p.src) <= p.pos {
+		return nil, p.errorf(p.pos, "項が空です。")
+	}
+	t, err := p.parseUnlambda()
+	if err != nil {
+		return nil, err
+	}
+	p.skipUnlambdaSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf(p.pos, "項の後に余分な文字があります。")
+	}
+	return t, nil
+}
+
+// parseUnlambda はUnlambdaの書式の項を1つ解析する。
+func (p *parser) parseUnlambda() (Term, error) {
+	p.skipUnlambdaSpace()
+	if len(p.src) <= p.pos {
+		return nil, p.errorf(p.pos, "関数適用の引数が足りません。")
+	}
+	start := p.pos
+	c := p.src[p.pos]
+	if c == '`' {
+		p.pos++
+		f, err := p.parseUnlambda()
+		if err != nil {
+			return nil, err
+		}
+		a, err := p.parseUnlambda()
+		if err != nil {
+			return nil, err
+		}
+		return &App{Fun: f, Arg: a}, nil
+	}
+	if un, ok := unlambdaNames[c]; ok {
+		a, err := lookupAtom(un.name, un.argc, p.cs)
+		if err != nil {
+			return nil, p.errorf(start, "%s", err)
+		}
+		p.pos++
+		return a, nil
+	}
+	if nm := p.matchName(); nm != "" {
+		p.pos += len(nm)
+		return &Atom{Name: nm}, nil
+	}
+	_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	return &Atom{Name: p.src[start:p.pos]}, nil
+}
+
+// skipUnlambdaSpace は空白文字と、「#」から行末までのコメントを読み飛ばす。
+func (p *parser) skipUnlambdaSpace() {
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '#' {
+			i := strings.IndexByte(p.src[p.pos:], '\n')
+			if i < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += i + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// FormatUnlambda は項をUnlambdaの書式の文字列に変換する。
+// S、K、Iはs、k、iに変換し、それ以外のAtomは名前をそのまま出力する。
+func FormatUnlambda(t Term) string {
+	var sb strings.Builder
+	writeUnlambda(&sb, t)
+	return sb.String()
+}
+
+// writeUnlambda は項をUnlambdaの書式で書き込む。
+func writeUnlambda(sb *strings.Builder, t Term) {
+	switch v := unparen(t).(type) {
+	case *App:
+		sb.WriteString("`")
+		writeUnlambda(sb, v.Fun)
+		writeUnlambda(sb, v.Arg)
+	case *Atom:
+		for c, un := range unlambdaNames {
+			if v.Name == un.name {
+				sb.WriteByte(c)
+				return
+			}
+		}
+		sb.WriteString(v.Name)
+	default:
+		v.writeTo(sb)
+	}
+}
@@ -0,0 +1,68 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnlambda(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "``skk", expect: "SKK", desc: "正常系"},
+		TD{clcode: "``s`kk`ki", expect: "S(KK)(KI)", desc: "引数の関数適用"},
+		TD{clcode: "```sxyz", expect: "Sxyz", desc: "s、k、i以外は1文字ずつ"},
+		TD{clcode: "` ` s k # コメント\n k", expect: "SKK", desc: "空白とコメントを読み飛ばす"},
+		TD{clcode: "i", expect: "I", desc: "関数適用なし"},
+	}
+	for _, td := range tds {
+		actual, err := ParseUnlambda(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, Canonical(actual), td.desc, td.clcode)
+	}
+
+	type TDE struct {
+		clcode string
+		cs     []Combinator
+		expect *ParseError
+		desc   string
+	}
+	tdes := []TDE{
+		TDE{clcode: "", cs: cs, expect: &ParseError{Line: 1, Column: 1, Msg: "項が空です。"}, desc: "空"},
+		TDE{clcode: "``sk", cs: cs, expect: &ParseError{Line: 1, Column: 5, Msg: "関数適用の引数が足りません。"}, desc: "引数が足りない"},
+		TDE{clcode: "`skk", cs: cs, expect: &ParseError{Line: 1, Column: 4, Msg: "項の後に余分な文字があります。"}, desc: "余分な文字"},
+		TDE{clcode: "`sk", cs: cs[1:], expect: &ParseError{Line: 1, Column: 2, Msg: "コンビネータS(引数3個)が定義されていません。"}, desc: "Sが定義されていない"},
+	}
+	for _, td := range tdes {
+		_, err := ParseUnlambda(td.clcode, td.cs)
+		assert.Equal(t, td.expect, err, td.desc)
+	}
+}
+
+func TestFormatUnlambda(t *testing.T) {
+	type TD struct {
+		clcode string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "SKK", expect: "``skk", desc: "正常系"},
+		TD{clcode: "S(KK)(KI)", expect: "``s`kk`ki", desc: "引数の関数適用"},
+		TD{clcode: "xz(yz)", expect: "``xz`yz", desc: "変数"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.clcode, cs)
+		assert.NoError(t, err, td.desc)
+		actual := FormatUnlambda(in)
+		assert.Equal(t, td.expect, actual, td.desc)
+
+		// 出力した文字列を解析すると同じ項になる
+		back, err := ParseUnlambda(actual, cs)
+		assert.NoError(t, err, td.desc)
+		assert.True(t, Equal(in, back), td.desc)
+	}
+}
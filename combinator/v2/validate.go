@@ -0,0 +1,105 @@
+package combinator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefinitionError はコンビネータ定義の誤りである。
+type DefinitionError struct {
+	// Index は誤りのあるコンビネータ定義の位置である。0から数える。
+	Index int
+	// Name は誤りのあるコンビネータ定義の名前である。
+	Name string
+	// Msg は誤りの内容である。
+	Msg string
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("%d番目のコンビネータ%s: %s", e.Index+1, e.Name, e.Msg)
+}
+
+// Validate はコンビネータ定義の誤りを全て返す。
+// 名前が空か使えない文字を含む場合、名前が重複している場合、引数の数が負の数の場合、
+// Formatを解析できない場合、Formatの引数の番号が引数の数以上の場合、
+// 引数のないコンビネータの参照が循環している場合を誤りとする。
+// 誤りがない場合はnilを返す。
+func Validate(cs []Combinator) []*DefinitionError {
+	var errs []*DefinitionError
+	for i, c := range cs {
+		errorf := func(format string, a ...interface{}) {
+			errs = append(errs, &DefinitionError{Index: i, Name: c.Name, Msg: fmt.Sprintf(format, a...)})
+		}
+
+		if c.Name == "" {
+			errorf("名前が空です。")
+		} else if j := strings.IndexFunc(c.Name, invalidNameRune); 0 <= j {
+			errorf("名前に使えない文字「%c」が含まれています。", []rune(c.Name[j:])[0])
+		}
+		for j, prev := range cs[:i] {
+			if c.Name != "" && c.Name == prev.Name {
+				errorf("名前が%d番目のコンビネータと重複しています。", j+1)
+				break
+			}
+		}
+		if c.ArgsCount < 0 {
+			errorf("引数の数%dが負の数です。", c.ArgsCount)
+		}
+
+		if err := c.CheckFormat(cs); err != nil {
+			if pe, ok := err.(*ParseError); ok {
+				errorf("Formatの%d文字目: %s", pe.Column, pe.Msg)
+			} else {
+				errorf("%s", err)
+			}
+			continue
+		}
+		f, _ := parseFormat(c.Format, c.Params, cs)
+		if n := maxHole(f); 0 <= n && c.ArgsCount <= n {
+			errorf("Formatの引数の番号%dが引数の数%dを超えています。", n, c.ArgsCount)
+		}
+	}
+	for _, cycle := range MacroCycles(cs) {
+		i := indexOf(cycle[0], cs)
+		errs = append(errs, &DefinitionError{Index: i, Name: cycle[0], Msg: fmt.Sprintf("引数のないコンビネータの参照が循環しています。循環=%s", strings.Join(cycle, " -> "))})
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Index < errs[j].Index
+	})
+	return errs
+}
+
+// indexOf は名前が一致する最初のコンビネータ定義の位置を返す。
+func indexOf(name string, cs []Combinator) int {
+	for i, c := range cs {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// invalidNameRune はコンビネータ名に使えない文字かを返す。
+func invalidNameRune(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("(){}", r)
+}
+
+// maxHole は項の中の引数の埋め込み位置の最大の番号を返す。
+// 引数の埋め込み位置がない場合は-1を返す。
+func maxHole(t Term) int {
+	switch v := t.(type) {
+	case *hole:
+		return v.Index
+	case *App:
+		f, a := maxHole(v.Fun), maxHole(v.Arg)
+		if f < a {
+			return a
+		}
+		return f
+	case *Paren:
+		return maxHole(v.Term)
+	}
+	return -1
+}
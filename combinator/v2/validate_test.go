@@ -0,0 +1,48 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(cs), "誤りがない")
+
+	in := []Combinator{
+		Combinator{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+		Combinator{Name: "", ArgsCount: 1, Format: "{0}"},
+		Combinator{Name: "K(", ArgsCount: 2, Format: "{0}"},
+		Combinator{Name: "S", ArgsCount: 3, Format: "{0}"},
+		Combinator{Name: "B", ArgsCount: 2, Format: "{0}({1}{3})"},
+		Combinator{Name: "C", ArgsCount: -1, Format: "x"},
+		Combinator{Name: "D", ArgsCount: 0, Format: "C(BC"},
+		Combinator{Name: "E", ArgsCount: 2, Format: "{x}", Params: []string{"x"}},
+	}
+	expect := []*DefinitionError{
+		&DefinitionError{Index: 1, Name: "", Msg: "名前が空です。"},
+		&DefinitionError{Index: 2, Name: "K(", Msg: "名前に使えない文字「(」が含まれています。"},
+		&DefinitionError{Index: 3, Name: "S", Msg: "名前が1番目のコンビネータと重複しています。"},
+		&DefinitionError{Index: 4, Name: "B", Msg: "Formatの引数の番号3が引数の数2を超えています。"},
+		&DefinitionError{Index: 5, Name: "C", Msg: "引数の数-1が負の数です。"},
+		&DefinitionError{Index: 6, Name: "D", Msg: "Formatの2文字目: 対応する閉じ括弧がありません。"},
+		&DefinitionError{Index: 7, Name: "E", Msg: "引数名の数1が引数の数2と一致しません。"},
+	}
+	assert.Equal(t, expect, Validate(in))
+
+	err := &DefinitionError{Index: 4, Name: "B", Msg: "Formatの引数の番号3が引数の数2を超えています。"}
+	assert.EqualError(t, err, "5番目のコンビネータB: Formatの引数の番号3が引数の数2を超えています。")
+}
+
+func TestValidateMacroCycle(t *testing.T) {
+	in := append([]Combinator{
+		Combinator{Name: "D", ArgsCount: 0, Format: "S(KE)"},
+		Combinator{Name: "E", ArgsCount: 0, Format: "K(SD)"},
+		Combinator{Name: "F", ArgsCount: 0, Format: "FI"},
+	}, cs...)
+	expect := []*DefinitionError{
+		&DefinitionError{Index: 0, Name: "D", Msg: "引数のないコンビネータの参照が循環しています。循環=D -> E -> D"},
+		&DefinitionError{Index: 2, Name: "F", Msg: "引数のないコンビネータの参照が循環しています。循環=F -> F"},
+	}
+	assert.Equal(t, expect, Validate(in))
+}
@@ -21,10 +21,10 @@ func WithOpen(fn string, f func(r io.Reader) error) error {
 	return f(r)
 }
 
-// WriteFile はファイル出力する。
+// WriteFile はファイル出力する。既存のファイルは内容を消してから書き込む。
 // 自動でファイルをクローズする。
 func WriteFile(fn string, lines []string) error {
-	w, err := os.OpenFile(fn, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	w, err := os.OpenFile(fn, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
 	if err != nil {
 		return err
 	}
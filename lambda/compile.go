@@ -0,0 +1,245 @@
+package lambda
+
+import (
+	"fmt"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+)
+
+// Abstraction はブラケット抽象のアルゴリズムである。
+type Abstraction int
+
+const (
+	// Naive はS、K、Iだけを使う素朴なブラケット抽象である。
+	Naive Abstraction = iota
+	// SKIEta はS、K、Iだけを使い、η変換で項を小さくするブラケット抽象である。
+	SKIEta
+	// Turner はB、Cなども使うTurnerの最適化したブラケット抽象である。
+	// S'、B*、C'は定義されている場合だけ使う。
+	Turner
+)
+
+// Abstractions はブラケット抽象のアルゴリズムの名前とアルゴリズムの対応である。
+var Abstractions = map[string]Abstraction{
+	"naive":   Naive,
+	"ski-eta": SKIEta,
+	"turner":  Turner,
+}
+
+func (a Abstraction) String() string {
+	for nm, v := range Abstractions {
+		if v == a {
+			return nm
+		}
+	}
+	return fmt.Sprintf("Abstraction(%d)", int(a))
+}
+
+// basisCombinator はブラケット抽象で使うコンビネータの名前と引数の数である。
+type basisCombinator struct {
+	name      string
+	argsCount int
+}
+
+var (
+	combS  = basisCombinator{name: "S", argsCount: 3}
+	combK  = basisCombinator{name: "K", argsCount: 2}
+	combI  = basisCombinator{name: "I", argsCount: 1}
+	combB  = basisCombinator{name: "B", argsCount: 3}
+	combC  = basisCombinator{name: "C", argsCount: 3}
+	combS2 = basisCombinator{name: "S'", argsCount: 4}
+	combB2 = basisCombinator{name: "B*", argsCount: 4}
+	combC2 = basisCombinator{name: "C'", argsCount: 4}
+)
+
+// required はブラケット抽象のアルゴリズムが必ず使うコンビネータを返す。
+func (a Abstraction) required() []basisCombinator {
+	if a == Turner {
+		return []basisCombinator{combS, combK, combI, combB, combC}
+	}
+	return []basisCombinator{combS, combK, combI}
+}
+
+// CheckBasis はブラケット抽象のアルゴリズムが使うコンビネータが全て定義されているかを返す。
+// 名前と引数の数が一致するコンビネータ定義がない場合はエラーを返す。
+func CheckBasis(a Abstraction, cs []combinator.Combinator) error {
+	for _, b := range a.required() {
+		if !defined(b, cs) {
+			return fmt.Errorf("ブラケット抽象%sに必要なコンビネータ%s(引数%d個)が定義されていません。", a, b.name, b.argsCount)
+		}
+	}
+	return nil
+}
+
+// defined はコンビネータが名前と引数の数が一致する形で定義されているかを返す。
+func defined(b basisCombinator, cs []combinator.Combinator) bool {
+	for _, c := range cs {
+		if c.Name == b.name && c.ArgsCount == b.argsCount {
+			return true
+		}
+	}
+	return false
+}
+
+// cl はブラケット抽象の途中で使うコンビネータ論理の項である。
+// 束縛変数とコンビネータを区別するため、combinator.Termとは別に持つ。
+type cl interface{}
+
+type (
+	// clVar は変数である。
+	clVar struct{ name string }
+	// clConst はコンビネータである。
+	clConst struct{ name string }
+	// clApp は関数適用である。
+	clApp struct{ fun, arg cl }
+)
+
+// Compile はラムダ式をブラケット抽象でコンビネータ論理の項に変換する。
+// 使うコンビネータは全てcsに定義されている必要があり、足りない場合はエラーを返す。
+// 自由変数は同じ名前のAtomになる。
+func Compile(t Term, a Abstraction, cs []combinator.Combinator) (combinator.Term, error) {
+	if err := CheckBasis(a, cs); err != nil {
+		return nil, err
+	}
+	c := compiler{
+		alg: a,
+		s2:  defined(combS2, cs),
+		b2:  defined(combB2, cs),
+		c2:  defined(combC2, cs),
+	}
+	return toTerm(c.compile(t)), nil
+}
+
+// compiler はラムダ式をコンビネータ論理の項に変換する。
+type compiler struct {
+	alg Abstraction
+	// s2、b2、c2 はそれぞれS'、B*、C'が使えるかどうかである。
+	s2, b2, c2 bool
+}
+
+// compile はラムダ式を内側のラムダ抽象から順にブラケット抽象で変換する。
+func (c compiler) compile(t Term) cl {
+	switch v := t.(type) {
+	case *Var:
+		return &clVar{name: v.Name}
+	case *App:
+		return &clApp{fun: c.compile(v.Fun), arg: c.compile(v.Arg)}
+	case *Abs:
+		return c.abstract(v.Param, c.compile(v.Body))
+	}
+	return nil
+}
+
+// abstract は項から変数xを取り除いたブラケット抽象[x]tを返す。
+//
+//	[x]x     = I
+//	[x]t     = K t     (tにxが現れない場合)
+//	[x](t x) = t       (tにxが現れない場合。Naive以外)
+//	[x](t u) = S ([x]t) ([x]u)
+//
+// Turnerの場合は最後の規則の結果をoptimizeで最適化する。
+func (c compiler) abstract(x string, t cl) cl {
+	if v, ok := t.(*clVar); ok && v.name == x {
+		return constant(combI)
+	}
+	if !occurs(x, t) {
+		return apply(constant(combK), t)
+	}
+	a := t.(*clApp)
+	if v, ok := a.arg.(*clVar); c.alg != Naive && ok && v.name == x && !occurs(x, a.fun) {
+		return a.fun
+	}
+	p, q := c.abstract(x, a.fun), c.abstract(x, a.arg)
+	if c.alg == Turner {
+		return c.optimize(p, q)
+	}
+	return apply(constant(combS), p, q)
+}
+
+// optimize はS p qをTurnerの規則で最適化した項を返す。
+//
+//	S (K p) (K q)   = K (p q)
+//	S (K p) I       = p
+//	S (K p) (B q r) = B* p q r
+//	S (K p) q       = B p q
+//	S (B p q) (K r) = C' p q r
+//	S p (K q)       = C p q
+//	S (B p q) r     = S' p q r
+func (c compiler) optimize(p, q cl) cl {
+	if kp, ok := args(p, combK, 1); ok {
+		if kq, ok := args(q, combK, 1); ok {
+			return apply(constant(combK), apply(kp[0], kq[0]))
+		}
+		if _, ok := args(q, combI, 0); ok {
+			return kp[0]
+		}
+		if bq, ok := args(q, combB, 2); ok && c.b2 {
+			return apply(constant(combB2), kp[0], bq[0], bq[1])
+		}
+		return apply(constant(combB), kp[0], q)
+	}
+	if bp, ok := args(p, combB, 2); ok && c.c2 {
+		if kq, ok := args(q, combK, 1); ok {
+			return apply(constant(combC2), bp[0], bp[1], kq[0])
+		}
+	}
+	if kq, ok := args(q, combK, 1); ok {
+		return apply(constant(combC), p, kq[0])
+	}
+	if bp, ok := args(p, combB, 2); ok && c.s2 {
+		return apply(constant(combS2), bp[0], bp[1], q)
+	}
+	return apply(constant(combS), p, q)
+}
+
+// args は項がコンビネータbにn個の引数を適用した項であれば、その引数を返す。
+func args(t cl, b basisCombinator, n int) ([]cl, bool) {
+	ret := make([]cl, n)
+	for i := n - 1; 0 <= i; i-- {
+		a, ok := t.(*clApp)
+		if !ok {
+			return nil, false
+		}
+		ret[i] = a.arg
+		t = a.fun
+	}
+	v, ok := t.(*clConst)
+	return ret, ok && v.name == b.name
+}
+
+// constant はコンビネータの項を返す。
+func constant(b basisCombinator) cl {
+	return &clConst{name: b.name}
+}
+
+// occurs は項に変数xが現れるかを返す。
+func occurs(x string, t cl) bool {
+	switch v := t.(type) {
+	case *clVar:
+		return v.name == x
+	case *clApp:
+		return occurs(x, v.fun) || occurs(x, v.arg)
+	}
+	return false
+}
+
+// apply は項に引数を左から順に適用した項を返す。
+func apply(t cl, args ...cl) cl {
+	for _, a := range args {
+		t = &clApp{fun: t, arg: a}
+	}
+	return t
+}
+
+// toTerm はブラケット抽象で変換した項をcombinator.Termに変換する。
+func toTerm(t cl) combinator.Term {
+	switch v := t.(type) {
+	case *clVar:
+		return &combinator.Atom{Name: v.name}
+	case *clConst:
+		return &combinator.Atom{Name: v.name}
+	case *clApp:
+		return &combinator.App{Fun: toTerm(v.fun), Arg: toTerm(v.arg)}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package lambda
+
+import (
+	"testing"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var ski = []combinator.Combinator{
+	combinator.Combinator{Name: "S", ArgsCount: 3, Format: "{0}{2}({1}{2})"},
+	combinator.Combinator{Name: "K", ArgsCount: 2, Format: "{0}"},
+	combinator.Combinator{Name: "I", ArgsCount: 1, Format: "{0}"},
+}
+
+var turner = append([]combinator.Combinator{
+	combinator.Combinator{Name: "B", ArgsCount: 3, Format: "{0}({1}{2})"},
+	combinator.Combinator{Name: "C", ArgsCount: 3, Format: "{0}{2}{1}"},
+	combinator.Combinator{Name: "S'", ArgsCount: 4, Format: "{0}({1}{3})({2}{3})"},
+	combinator.Combinator{Name: "B*", ArgsCount: 4, Format: "{0}({1}({2}{3}))"},
+	combinator.Combinator{Name: "C'", ArgsCount: 4, Format: "{0}({1}{3}){2}"},
+}, ski...)
+
+func TestCompile(t *testing.T) {
+	type TD struct {
+		s      string
+		a      Abstraction
+		cs     []combinator.Combinator
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{s: `\x.x`, a: Naive, cs: ski, expect: "I", desc: "恒等関数"},
+		TD{s: `\x.y`, a: Naive, cs: ski, expect: "K y", desc: "変数が現れない"},
+		TD{s: `\x.\y.x`, a: Naive, cs: ski, expect: "S (K K) I", desc: "K"},
+		TD{s: `\x.x x`, a: Naive, cs: ski, expect: "S I I", desc: "関数適用"},
+		TD{s: `foo bar`, a: Naive, cs: ski, expect: "foo bar", desc: "自由変数はそのまま"},
+		TD{s: `\x.\y.y x`, a: Naive, cs: ski, expect: "S (K (S I)) (S (K K) I)", desc: "Naive"},
+		TD{s: `\x.\y.x`, a: SKIEta, cs: ski, expect: "K", desc: "SKIEta:η変換する"},
+		TD{s: `\x.\y.y x`, a: SKIEta, cs: ski, expect: "S (K (S I)) K", desc: "SKIEta"},
+		TD{s: `\x.\y.y x`, a: Turner, cs: turner, expect: "C I", desc: "Turner:C"},
+		TD{s: `\f.\g.\x.f (g x)`, a: Turner, cs: turner, expect: "B", desc: "Turner:B"},
+		TD{s: `\x.f (g x) (h x)`, a: Turner, cs: turner, expect: "S' f g h", desc: "Turner:S'"},
+		TD{s: `\x.f (g (h x))`, a: Turner, cs: turner, expect: "B* f g h", desc: "Turner:B*"},
+		TD{s: `\x.f (g x) h`, a: Turner, cs: turner, expect: "C' f g h", desc: "Turner:C'"},
+		TD{s: `\x.f (g x) (h x)`, a: Turner, cs: append(turner[:2:2], ski...), expect: "S (B f g) h", desc: "Turner:S'が定義されていなければ使わない"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.s)
+		assert.NoError(t, err, td.desc)
+		actual, err := Compile(in, td.a, td.cs)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, combinator.Printer{Spaced: true}.Print(actual), td.desc, td.s)
+	}
+}
+
+func TestCompileReduce(t *testing.T) {
+	// 変換した項はラムダ式と同じように計算される
+	type TD struct {
+		s      string
+		args   string
+		expect string
+	}
+	tds := []TD{
+		TD{s: `\x.\y.x`, args: "ab", expect: "a"},
+		TD{s: `\x.\y.y x`, args: "ab", expect: "ba"},
+		TD{s: `\f.\g.\x.f (g x)`, args: "fgx", expect: "f(gx)"},
+		TD{s: `\K.\x.K`, args: "ab", expect: "a"},
+		TD{s: `\x.\y.\z.x z (y z) (y (x z))`, args: "abc", expect: "ac(bc)(b(ac))"},
+	}
+	for _, td := range tds {
+		in, err := Parse(td.s)
+		assert.NoError(t, err, td.s)
+		for _, a := range []Abstraction{Naive, SKIEta, Turner} {
+			compiled, err := Compile(in, a, turner)
+			assert.NoError(t, err, td.s)
+			clcode := "(" + combinator.Canonical(compiled) + ")" + td.args
+			actual, err := combinator.CalcCLCode(clcode, turner, -1, combinator.Normal)
+			assert.NoError(t, err, td.s)
+			assert.Equal(t, td.expect, actual, td.s, a)
+		}
+	}
+}
+
+func TestCheckBasis(t *testing.T) {
+	assert.NoError(t, CheckBasis(Naive, ski))
+	assert.NoError(t, CheckBasis(SKIEta, ski))
+	assert.NoError(t, CheckBasis(Turner, turner))
+
+	err := CheckBasis(Turner, ski)
+	assert.EqualError(t, err, "ブラケット抽象turnerに必要なコンビネータB(引数3個)が定義されていません。")
+
+	err = CheckBasis(Naive, []combinator.Combinator{ski[0], ski[1], combinator.Combinator{Name: "I", ArgsCount: 2}})
+	assert.EqualError(t, err, "ブラケット抽象naiveに必要なコンビネータI(引数1個)が定義されていません。", "引数の数が違う")
+
+	in, err := Parse(`\x.x`)
+	assert.NoError(t, err)
+	_, err = Compile(in, Turner, ski)
+	assert.Error(t, err)
+}
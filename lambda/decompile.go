@@ -0,0 +1,128 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+)
+
+// freshNames は新しい変数名の候補である。
+// 候補を使い切った場合は末尾に数字を付けて使う。
+var freshNames = []string{"x", "y", "z", "w", "v", "u"}
+
+// Decompile はコンビネータ論理の項と同じ振る舞いをするラムダ式を返す。
+// 引数が足りないコンビネータには新しい変数を適用して計算し、その変数のラムダ抽象にする。
+// 計算にはrのコンビネータ定義と上限を使い、全体でr.MaxStepsステップまで計算する。
+// 簡約戦略はrの指定に関わらずWeakHeadで計算する。
+// 上限を超えるか、ctxがキャンセルされるか期限を過ぎた場合はエラーを返す。
+func Decompile(ctx context.Context, t combinator.Term, r combinator.Reducer) (Term, error) {
+	r.Strategy, r.DetectCycle, r.OnStep = combinator.WeakHead, true, nil
+	d := &decompiler{
+		ctx:   ctx,
+		r:     r,
+		cs:    r.Combinators,
+		steps: r.MaxSteps,
+		used:  map[string]bool{},
+	}
+	for _, c := range d.cs {
+		d.used[c.Name] = true
+	}
+	d.markUsed(t)
+	return d.decompile(t)
+}
+
+// decompiler はコンビネータ論理の項をラムダ式に変換する。
+type decompiler struct {
+	ctx context.Context
+	// r は計算に使うReducerである。MaxStepsは計算のたびにstepsで置き換える。
+	r  combinator.Reducer
+	cs []combinator.Combinator
+	// steps は計算できる残りのステップ数である。-1の場合は上限なしである。
+	steps int
+	// used は新しい変数名として使えない名前である。
+	used map[string]bool
+	// n は次に使う新しい変数名の候補の番号である。
+	n int
+}
+
+// decompile は項の先頭が計算できなくなるまで計算し、ラムダ式に変換する。
+// 引数は先頭を変換したあとでそれぞれ変換する。
+func (d *decompiler) decompile(t combinator.Term) (Term, error) {
+	r := d.r
+	r.MaxSteps = d.steps
+	ret := r.ReduceContext(d.ctx, t)
+	if 0 <= d.steps {
+		d.steps -= ret.Steps
+	}
+	switch ret.Outcome {
+	case combinator.NormalForm:
+	case combinator.StepLimit:
+		if _, ok := combinator.WeakHead.Step(ret.Term, d.cs); ok {
+			return nil, fmt.Errorf("ラムダ式への変換が計算ステップ数の上限までに終わりませんでした。")
+		}
+	default:
+		return nil, fmt.Errorf("ラムダ式に変換できませんでした。%s", ret.Message())
+	}
+
+	head, args := combinator.Spine(ret.Term)
+	a, ok := head.(*combinator.Atom)
+	if !ok {
+		return nil, fmt.Errorf("ラムダ式に変換できない項です。項=%s", combinator.Canonical(head))
+	}
+	if c, ok := d.find(a.Name); ok && len(args) < c.ArgsCount {
+		v := d.fresh()
+		body, err := d.decompile(combinator.Apply(ret.Term, &combinator.Atom{Name: v}))
+		if err != nil {
+			return nil, err
+		}
+		return &Abs{Param: v, Body: body}, nil
+	}
+
+	var lt Term = &Var{Name: a.Name}
+	for _, arg := range args {
+		la, err := d.decompile(arg)
+		if err != nil {
+			return nil, err
+		}
+		lt = &App{Fun: lt, Arg: la}
+	}
+	return lt, nil
+}
+
+// find は名前に一致するコンビネータ定義を返す。
+func (d *decompiler) find(name string) (combinator.Combinator, bool) {
+	for _, c := range d.cs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return combinator.Combinator{}, false
+}
+
+// fresh は項にもコンビネータ定義にも現れない新しい変数名を返す。
+func (d *decompiler) fresh() string {
+	for {
+		nm := freshNames[d.n%len(freshNames)]
+		if round := d.n / len(freshNames); 0 < round {
+			nm += strconv.Itoa(round)
+		}
+		d.n++
+		if !d.used[nm] {
+			d.used[nm] = true
+			return nm
+		}
+	}
+}
+
+// markUsed は項に現れるAtomの名前を新しい変数名として使えないようにする。
+func (d *decompiler) markUsed(t combinator.Term) {
+	head, args := combinator.Spine(t)
+	if a, ok := head.(*combinator.Atom); ok {
+		d.used[a.Name] = true
+	}
+	for _, arg := range args {
+		d.markUsed(arg)
+	}
+}
@@ -0,0 +1,74 @@
+package lambda
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompile(t *testing.T) {
+	type TD struct {
+		clcode string
+		cs     []combinator.Combinator
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{clcode: "S(K(SI))K", cs: ski, expect: `\x.\y.y x`, desc: "正常系"},
+		TD{clcode: "K", cs: ski, expect: `\x.\y.x`, desc: "コンビネータ単体"},
+		TD{clcode: "SKK", cs: ski, expect: `\x.x`, desc: "計算してから変換する"},
+		TD{clcode: "ab(Ic)", cs: ski, expect: `a b c`, desc: "引数も変換する"},
+		TD{clcode: "a(Kb)", cs: ski, expect: `a (\x.b)`, desc: "引数のコンビネータも変換する"},
+		TD{clcode: "Kx", cs: ski, expect: `\y.x`, desc: "項に現れる変数名は使わない"},
+		TD{clcode: "SB(SB(KI))", cs: turner, expect: `\x.\y.x (x y)`, desc: "定義済みのコンビネータを使う"},
+	}
+	for _, td := range tds {
+		in, err := combinator.Parse(td.clcode, td.cs)
+		assert.NoError(t, err, td.desc)
+		actual, err := Decompile(context.Background(), in, combinator.Reducer{Combinators: td.cs, MaxSteps: -1})
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual.String(), td.desc, td.clcode)
+	}
+}
+
+func TestDecompileRoundTrip(t *testing.T) {
+	// ラムダ式を変換して戻すと同じ振る舞いのラムダ式になる
+	for _, s := range []string{`\x.\y.y x`, `\x.\y.\z.x z (y z)`, `\x.\y.x (x (x y))`} {
+		in, err := Parse(s)
+		assert.NoError(t, err)
+		for _, a := range []Abstraction{Naive, SKIEta, Turner} {
+			compiled, err := Compile(in, a, turner)
+			assert.NoError(t, err)
+			actual, err := Decompile(context.Background(), compiled, combinator.Reducer{Combinators: turner, MaxSteps: -1})
+			assert.NoError(t, err)
+			assert.Equal(t, in.String(), actual.String(), s, a)
+		}
+	}
+}
+
+func TestDecompileError(t *testing.T) {
+	m := append([]combinator.Combinator{combinator.Combinator{Name: "M", ArgsCount: 1, Format: "{0}{0}"}}, ski...)
+	in, err := combinator.Parse("MM", m)
+	assert.NoError(t, err)
+	_, err = Decompile(context.Background(), in, combinator.Reducer{Combinators: m, MaxSteps: -1})
+	assert.EqualError(t, err, "ラムダ式に変換できませんでした。cycle of period 1 detected at step 1")
+
+	in, err = combinator.Parse("S(SKK)(SKK)(S(SKK)(SKK))", ski)
+	assert.NoError(t, err)
+	_, err = Decompile(context.Background(), in, combinator.Reducer{Combinators: ski, MaxSteps: 100})
+	assert.EqualError(t, err, "ラムダ式への変換が計算ステップ数の上限までに終わりませんでした。")
+
+	_, err = Decompile(context.Background(), in, combinator.Reducer{Combinators: ski, MaxSteps: -1, MaxSize: 50})
+	assert.EqualError(t, err, "ラムダ式に変換できませんでした。size limit exceeded at step 17")
+
+	_, err = Decompile(context.Background(), in, combinator.Reducer{Combinators: ski, MaxSteps: -1, MaxDepth: 10})
+	assert.EqualError(t, err, "ラムダ式に変換できませんでした。depth limit exceeded at step 17")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = Decompile(ctx, in, combinator.Reducer{Combinators: ski, MaxSteps: -1})
+	assert.Error(t, err, "期限を過ぎる")
+}
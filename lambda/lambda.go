@@ -0,0 +1,72 @@
+package lambda
+
+import "strings"
+
+// Term はラムダ式の項である。
+type Term interface {
+	String() string
+	writeTo(sb *strings.Builder)
+}
+
+// Var は変数である。
+type Var struct {
+	Name string
+}
+
+// Abs はラムダ抽象である。
+// Paramを引数に取りBodyを返す関数を表す。
+type Abs struct {
+	Param string
+	Body  Term
+}
+
+// App は関数適用である。
+// FunにArgを適用する。
+type App struct {
+	Fun Term
+	Arg Term
+}
+
+func (v *Var) String() string { return termString(v) }
+func (a *Abs) String() string { return termString(a) }
+func (a *App) String() string { return termString(a) }
+
+func (v *Var) writeTo(sb *strings.Builder) {
+	sb.WriteString(v.Name)
+}
+
+func (a *Abs) writeTo(sb *strings.Builder) {
+	sb.WriteString(`\`)
+	sb.WriteString(a.Param)
+	sb.WriteString(".")
+	a.Body.writeTo(sb)
+}
+
+func (a *App) writeTo(sb *strings.Builder) {
+	// 関数のラムダ抽象は括弧で括らないと本体が引数まで続いてしまう
+	if _, ok := a.Fun.(*Abs); ok {
+		writeParen(sb, a.Fun)
+	} else {
+		a.Fun.writeTo(sb)
+	}
+	sb.WriteString(" ")
+	if _, ok := a.Arg.(*Var); ok {
+		a.Arg.writeTo(sb)
+		return
+	}
+	writeParen(sb, a.Arg)
+}
+
+// writeParen は項を括弧で括って書き込む。
+func writeParen(sb *strings.Builder, t Term) {
+	sb.WriteString("(")
+	t.writeTo(sb)
+	sb.WriteString(")")
+}
+
+// termString は項を文字列に変換する。
+func termString(t Term) string {
+	var sb strings.Builder
+	t.writeTo(&sb)
+	return sb.String()
+}
@@ -0,0 +1,44 @@
+package lambda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermString(t *testing.T) {
+	var (
+		x = &Var{Name: "x"}
+		y = &Var{Name: "y"}
+	)
+	type TD struct {
+		t      Term
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			t:      &Abs{Param: "x", Body: &Abs{Param: "y", Body: &App{Fun: y, Arg: x}}},
+			expect: `\x.\y.y x`,
+			desc:   "ラムダ抽象",
+		},
+		TD{
+			t:      &App{Fun: &Abs{Param: "x", Body: x}, Arg: y},
+			expect: `(\x.x) y`,
+			desc:   "関数のラムダ抽象は括弧で括る",
+		},
+		TD{
+			t:      &App{Fun: x, Arg: &App{Fun: y, Arg: &Abs{Param: "x", Body: x}}},
+			expect: `x (y (\x.x))`,
+			desc:   "引数の関数適用とラムダ抽象は括弧で括る",
+		},
+		TD{
+			t:      &App{Fun: &App{Fun: x, Arg: y}, Arg: x},
+			expect: `x y x`,
+			desc:   "関数適用は左結合",
+		},
+	}
+	for _, td := range tds {
+		assert.Equal(t, td.expect, td.t.String(), td.desc)
+	}
+}
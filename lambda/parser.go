@@ -0,0 +1,157 @@
+package lambda
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+)
+
+// Parse はラムダ式を解析して項を返す。
+// ラムダ抽象は「\x.本体」または「λx.本体」と書き、「\x y.本体」のように
+// 複数の引数をまとめて書くこともできる。
+// 変数は空白、括弧、「\」、「λ」、「.」以外の文字の並びである。
+// 解析できない場合は*combinator.ParseErrorを返す。
+func Parse(s string) (Term, error) {
+	p := &parser{src: s}
+	t, err := p.parseSeq()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf(p.pos, "対応する開き括弧がありません。")
+	}
+	if t == nil {
+		return nil, p.errorf(p.pos, "項が空です。")
+	}
+	return t, nil
+}
+
+// parser はラムダ式の構文解析器である。
+type parser struct {
+	src string
+	pos int
+}
+
+// parseSeq は閉じ括弧か入力の終わりまでの項の並びを左結合の関数適用として解析する。
+// 項がひとつもない場合はnilを返す。
+func (p *parser) parseSeq() (Term, error) {
+	var t Term
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] == ')' {
+			return t, nil
+		}
+		item, err := p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			t = item
+			continue
+		}
+		t = &App{Fun: t, Arg: item}
+	}
+}
+
+// parseItem は括弧で括られた項、ラムダ抽象、または変数を1つ解析する。
+func (p *parser) parseItem() (Term, error) {
+	start := p.pos
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	switch r {
+	case '(':
+		p.pos += size
+		t, err := p.parseSeq()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf(start, "対応する閉じ括弧がありません。")
+		}
+		p.pos++
+		if t == nil {
+			return nil, p.errorf(start, "括弧の中が空です。")
+		}
+		return t, nil
+	case '\\', 'λ':
+		p.pos += size
+		return p.parseAbs(start)
+	case '.':
+		return nil, p.errorf(start, "ラムダ抽象の外に「.」があります。")
+	}
+	return &Var{Name: p.parseIdent()}, nil
+}
+
+// parseAbs はラムダ抽象の引数と本体を解析する。
+// 本体は閉じ括弧か入力の終わりまで続く。
+func (p *parser) parseAbs(start int) (Term, error) {
+	var params []string
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '.' {
+			p.pos++
+			break
+		}
+		nm := p.parseIdent()
+		if nm == "" {
+			return nil, p.errorf(p.pos, "ラムダ抽象の引数がありません。")
+		}
+		params = append(params, nm)
+	}
+	if len(params) < 1 {
+		return nil, p.errorf(start, "ラムダ抽象の引数がありません。")
+	}
+	body, err := p.parseSeq()
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, p.errorf(start, "ラムダ抽象の本体がありません。")
+	}
+	for i := len(params) - 1; 0 <= i; i-- {
+		body = &Abs{Param: params[i], Body: body}
+	}
+	return body, nil
+}
+
+// parseIdent は現在位置から変数名の終わりまでを読み進め、変数名を返す。
+func (p *parser) parseIdent() string {
+	s := p.src[p.pos:]
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`()\λ.`, r)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	p.pos += i
+	return s[:i]
+}
+
+// errorf は入力のバイト位置posの構文エラーを返す。
+func (p *parser) errorf(pos int, format string, a ...interface{}) *combinator.ParseError {
+	var (
+		before = p.src[:pos]
+		line   = strings.Count(before, "\n") + 1
+	)
+	if i := strings.LastIndexByte(before, '\n'); 0 <= i {
+		before = before[i+1:]
+	}
+	return &combinator.ParseError{
+		Line:   line,
+		Column: utf8.RuneCountInString(before) + 1,
+		Msg:    fmt.Sprintf(format, a...),
+	}
+}
+
+// skipSpace は空白文字を読み飛ばす。
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
@@ -0,0 +1,70 @@
+package lambda
+
+import (
+	"testing"
+
+	combinator "github.com/jiro4989/colc/combinator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	var (
+		x   = &Var{Name: "x"}
+		y   = &Var{Name: "y"}
+		foo = &Var{Name: "foo"}
+	)
+	type TD struct {
+		s      string
+		expect Term
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			s:      `\x.\y.x`,
+			expect: &Abs{Param: "x", Body: &Abs{Param: "y", Body: x}},
+			desc:   "ラムダ抽象",
+		},
+		TD{
+			s:      `λx.λy.x`,
+			expect: &Abs{Param: "x", Body: &Abs{Param: "y", Body: x}},
+			desc:   "λでもラムダ抽象を書ける",
+		},
+		TD{
+			s:      `\x y. y x`,
+			expect: &Abs{Param: "x", Body: &Abs{Param: "y", Body: &App{Fun: y, Arg: x}}},
+			desc:   "複数の引数をまとめて書ける",
+		},
+		TD{
+			s:      `(\x.x) foo y`,
+			expect: &App{Fun: &App{Fun: &Abs{Param: "x", Body: x}, Arg: foo}, Arg: y},
+			desc:   "関数適用は左結合",
+		},
+		TD{
+			s:      `x \y.y x`,
+			expect: &App{Fun: x, Arg: &Abs{Param: "y", Body: &App{Fun: y, Arg: x}}},
+			desc:   "ラムダ抽象の本体は最後まで続く",
+		},
+	}
+	for _, td := range tds {
+		actual, err := Parse(td.s)
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc, td.s)
+	}
+
+	type ETD struct {
+		s      string
+		expect *combinator.ParseError
+	}
+	etds := []ETD{
+		ETD{s: `\x.(x`, expect: &combinator.ParseError{Line: 1, Column: 4, Msg: "対応する閉じ括弧がありません。"}},
+		ETD{s: `\.x`, expect: &combinator.ParseError{Line: 1, Column: 1, Msg: "ラムダ抽象の引数がありません。"}},
+		ETD{s: `\x.`, expect: &combinator.ParseError{Line: 1, Column: 1, Msg: "ラムダ抽象の本体がありません。"}},
+		ETD{s: `\x`, expect: &combinator.ParseError{Line: 1, Column: 3, Msg: "ラムダ抽象の引数がありません。"}},
+		ETD{s: `x.y`, expect: &combinator.ParseError{Line: 1, Column: 2, Msg: "ラムダ抽象の外に「.」があります。"}},
+		ETD{s: `x)`, expect: &combinator.ParseError{Line: 1, Column: 2, Msg: "対応する開き括弧がありません。"}},
+	}
+	for _, td := range etds {
+		_, err := Parse(td.s)
+		assert.Equal(t, td.expect, err, td.s)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jiro4989/colc/basis"
+	combinator "github.com/jiro4989/colc/combinator/v2"
+)
+
+// basisPrefix は組み込みのコンビネータ定義を読み込むパスの接頭辞である。
+// 「basis:ski」のように組み込みの定義の名前を続ける。
+const basisPrefix = "basis:"
+
+// definitionLoader はimportsを辿ってコンビネータ定義ファイルを読み込み、定義を重ねる。
+// 同じ名前の定義は後から読み込んだ定義で置き換え、置き換えた場合は警告を記録する。
+type definitionLoader struct {
+	cs       Combinators
+	warnings []string
+	// origins はコンビネータ名ごとの定義を読み込んだパスである。
+	origins map[string]string
+	// loaded は読み込み済みのパスで、同じファイルを2回読み込まないために使う。
+	loaded map[string]bool
+	// stack は読み込み中のパスで、importsの循環を検出するために使う。
+	stack []string
+}
+
+func newDefinitionLoader() *definitionLoader {
+	return &definitionLoader{
+		origins: map[string]string{},
+		loaded:  map[string]bool{},
+	}
+}
+
+// load はパスのコンビネータ定義を読み込んだ定義に重ねる。
+// importsで指定されたファイルを先に読み込み、usesの名前空間の修飾なしの名前を重ね、
+// その後にファイル自身の定義を重ねる。
+// importsのパスはimportsを書いたファイルのディレクトリからの相対パスとする。
+// 名前空間を宣言したファイルの定義は、名前空間で修飾した名前で重ねる。
+// パスが「basis:」で始まる場合は組み込みのコンビネータ定義を読み込む。
+func (l *definitionLoader) load(path string) error {
+	if !strings.HasPrefix(path, basisPrefix) {
+		path = filepath.Clean(path)
+	}
+	for i, p := range l.stack {
+		if p == path {
+			cycle := append(append([]string(nil), l.stack[i:]...), path)
+			return fmt.Errorf("%s: コンビネータ定義ファイルのimportsが循環しています。循環=%s", path, strings.Join(cycle, " -> "))
+		}
+	}
+	if l.loaded[path] {
+		return nil
+	}
+
+	if strings.HasPrefix(path, basisPrefix) {
+		name := strings.TrimPrefix(path, basisPrefix)
+		b, ok := basis.Lookup(name)
+		if !ok {
+			return fmt.Errorf("不正な組み込みのコンビネータ定義です。basis=%s", name)
+		}
+		l.add(path, b.Combinators)
+		l.loaded[path] = true
+		return nil
+	}
+
+	file, err := readDefinitionFile(path)
+	if err != nil {
+		return err
+	}
+	l.stack = append(l.stack, path)
+	for _, imp := range file.Imports {
+		if !strings.HasPrefix(imp, basisPrefix) && !filepath.IsAbs(imp) {
+			imp = filepath.Join(filepath.Dir(path), imp)
+		}
+		if err := l.load(imp); err != nil {
+			return err
+		}
+	}
+	l.stack = l.stack[:len(l.stack)-1]
+	for _, ns := range file.Uses {
+		if err := l.use(path, ns); err != nil {
+			return err
+		}
+	}
+	if err := file.parseEquations(l.cs); err != nil {
+		return err
+	}
+	cs := file.Combinators
+	if file.Namespace != "" {
+		if cs, err = combinator.Qualify(file.Namespace, cs, l.cs); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	l.add(path, cs)
+	l.loaded[path] = true
+	return nil
+}
+
+// use は名前空間nsの読み込み済みの定義を、修飾なしの名前で重ねる。
+// 名前空間の定義がない場合はエラーを返す。
+func (l *definitionLoader) use(origin, ns string) error {
+	cs := combinator.Unqualify(ns, l.cs)
+	if len(cs) < 1 {
+		return fmt.Errorf("%s: 名前空間%sのコンビネータが読み込まれていません。", origin, ns)
+	}
+	l.add(origin, cs)
+	return nil
+}
+
+// add はoriginから読み込んだコンビネータ定義を重ねる。
+// 異なる内容の同じ名前の定義を置き換える場合は警告を記録する。
+func (l *definitionLoader) add(origin string, cs Combinators) {
+	for _, c := range cs {
+		prev, ok := l.origins[c.Name]
+		if ok {
+			for _, old := range l.cs {
+				if old.Name == c.Name && !reflect.DeepEqual(old, c) {
+					l.warnings = append(l.warnings, fmt.Sprintf("%sのコンビネータ%sの定義を%sの定義で上書きします。", prev, c.Name, origin))
+					break
+				}
+			}
+		}
+		l.origins[c.Name] = origin
+	}
+	l.cs = basis.Merge(l.cs, cs)
+}
+
+// check は重ねたコンビネータ定義の誤りを返す。
+// ファイルごとの検査では見つからない、ファイルをまたいだ引数のないコンビネータの参照の循環を誤りとする。
+func (l *definitionLoader) check() error {
+	var lines []string
+	for _, cycle := range combinator.MacroCycles(l.cs) {
+		lines = append(lines, fmt.Sprintf("%s: コンビネータ%s: 引数のないコンビネータの参照が循環しています。循環=%s", l.origins[cycle[0]], cycle[0], strings.Join(cycle, " -> ")))
+	}
+	if 0 < len(lines) {
+		return fmt.Errorf("%s", strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// splitDirectives は等式で書かれた定義ファイルから「namespace 名前空間」、「import パス」、
+// 「use 名前空間」の行を読み取る。
+// 読み取った行は行番号が変わらないように空行に置き換え、残りの内容と一緒に返す。
+// 名前空間を2回以上宣言した場合は*combinator.ParseErrorを返す。
+func splitDirectives(src string) (definitionFile, string, error) {
+	var file definitionFile
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		if j := strings.IndexByte(line, '#'); 0 <= j {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.Contains(line, "=") {
+			continue
+		}
+		arg := strings.TrimSpace(strings.TrimSpace(line)[len(fields[0]):])
+		switch fields[0] {
+		case "namespace":
+			if file.Namespace != "" {
+				return file, "", &combinator.ParseError{Line: i + 1, Column: strings.Index(line, "namespace") + 1, Msg: "名前空間が重複して宣言されています。"}
+			}
+			file.Namespace = arg
+		case "import":
+			file.Imports = append(file.Imports, arg)
+		case "use":
+			file.Uses = append(file.Uses, arg)
+		default:
+			continue
+		}
+		lines[i] = ""
+	}
+	return file, strings.Join(lines, "\n"), nil
+}
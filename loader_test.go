@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jiro4989/colc/basis"
+	combinator "github.com/jiro4989/colc/combinator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitionLoader(t *testing.T) {
+	names := func(cs Combinators) []string {
+		var ret []string
+		for _, c := range cs {
+			ret = append(ret, c.Name)
+		}
+		return ret
+	}
+	type TD struct {
+		paths    []string
+		expect   []string
+		warnings []string
+		desc     string
+	}
+	tds := []TD{
+		TD{
+			paths:  []string{"testdata/in/imports/numbers.json"},
+			expect: []string{"S", "K", "I", "B", "<zero>", "<suc>", "<one>"},
+			desc:   "importsのファイルの定義に追加する",
+		},
+		TD{
+			paths:    []string{"testdata/in/imports/override.def"},
+			expect:   []string{"S", "K", "I", "B", "<zero>", "<suc>", "<one>", "<two>"},
+			warnings: []string{"testdata/in/imports/numbers.jsonのコンビネータ<zero>の定義をtestdata/in/imports/override.defの定義で上書きします。"},
+			desc:     "同じファイルは1回だけ読み込み、異なる定義の上書きは警告する",
+		},
+		TD{
+			paths:  []string{"basis:bckw", "testdata/in/imports/base.def"},
+			expect: []string{"B", "C", "K", "W", "S", "I"},
+			desc:   "組み込みの定義にファイルの定義を追加する。同じ内容の定義の上書きは警告しない",
+		},
+		TD{
+			paths:  []string{"testdata/in/namespace/main.def"},
+			expect: []string{"S", "K", "I", "church.B", "church.<zero>", "church.<suc>", "church.<one>", "scott.C", "scott.<zero>", "scott.<suc>", "scott.<one>", "B", "<zero>", "<suc>", "<one>", "<two>", "<pair>"},
+			desc:   "名前空間で修飾した定義と、usesで修飾なしの名前にした定義",
+		},
+		TD{
+			paths:  []string{"testdata/in/imports/base.def", "basis:birds"},
+			expect: append([]string{"S", "K", "I"}, names(basis.Birds.Combinators)...),
+			desc:   "名前が異なれば上書きしない",
+		},
+	}
+	for _, td := range tds {
+		l := newDefinitionLoader()
+		for _, path := range td.paths {
+			assert.NoError(t, l.load(path), td.desc)
+		}
+		assert.NoError(t, l.check(), td.desc)
+		assert.Equal(t, td.expect, names(l.cs), td.desc)
+		assert.Equal(t, td.warnings, l.warnings, td.desc)
+	}
+}
+
+func TestDefinitionLoaderImportedNames(t *testing.T) {
+	l := newDefinitionLoader()
+	assert.NoError(t, l.load("testdata/in/namespace/church_ref.def"))
+	type TD struct {
+		name   string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{name: "F", expect: "church.<one>{0}", desc: "読み込んだ修飾名と1文字の引数名を区切る"},
+		TD{name: "G", expect: "K(church.<suc>{0})", desc: "括弧の中の読み込んだ修飾名と1文字の引数名を区切る"},
+	}
+	formats := map[string]string{}
+	for _, c := range l.cs {
+		formats[c.Name] = c.Format
+	}
+	for _, td := range tds {
+		assert.Equal(t, td.expect, formats[td.name], td.desc)
+	}
+
+	_, err := ReadCombinator("testdata/in/namespace/church_ref.def")
+	assert.EqualError(t, err, "testdata/in/namespace/church_ref.def:3:7: 引数名cが定義されていない名前church.<one>の一部として解析されます。", "importsを読み込まなければ引数名を含む不明な名前は誤り")
+}
+
+func TestDefinitionLoaderError(t *testing.T) {
+	type TD struct {
+		path   string
+		expect string
+		desc   string
+	}
+	tds := []TD{
+		TD{path: "testdata/in/imports/cycle_a.json", expect: "testdata/in/imports/cycle_a.json: コンビネータ定義ファイルのimportsが循環しています。循環=testdata/in/imports/cycle_a.json -> testdata/in/imports/cycle_b.def -> testdata/in/imports/cycle_a.json", desc: "importsの循環"},
+		TD{path: "testdata/in/imports/macro_a.json", expect: "testdata/in/imports/macro_b.json: コンビネータY: 引数のないコンビネータの参照が循環しています。循環=Y -> X -> Y", desc: "ファイルをまたいだ引数のないコンビネータの参照の循環"},
+		TD{path: "testdata/in/imports/unknown_key.json", expect: "testdata/in/imports/unknown_key.json: 不明なキーimportがあります。", desc: "不明なキー"},
+		TD{path: "testdata/in/namespace/bad_namespace.json", expect: "testdata/in/namespace/bad_namespace.json: 名前空間a.bに使えない文字「.」が含まれています。", desc: "名前空間に使えない文字"},
+		TD{path: "testdata/in/namespace/unknown_use.def", expect: "testdata/in/namespace/unknown_use.def: 名前空間scottのコンビネータが読み込まれていません。", desc: "読み込まれていない名前空間"},
+		TD{path: "basis:foo", expect: "不正な組み込みのコンビネータ定義です。basis=foo", desc: "存在しない組み込みの定義"},
+	}
+	for _, td := range tds {
+		l := newDefinitionLoader()
+		err := l.load(td.path)
+		if err == nil {
+			err = l.check()
+		}
+		assert.EqualError(t, err, td.expect, td.desc)
+	}
+}
+
+func TestSplitDirectives(t *testing.T) {
+	file, src, err := splitDirectives("namespace church\nimport a.def # コメント\nS x y z = x z (y z)\nimport = x\n  import  basis:ski\nuse scott\n")
+	assert.NoError(t, err)
+	assert.Equal(t, definitionFile{Namespace: "church", Imports: []string{"a.def", "basis:ski"}, Uses: []string{"scott"}}, file)
+	assert.Equal(t, "\n\nS x y z = x z (y z)\nimport = x\n\n\n", src, "行番号が変わらないように空行に置き換える")
+
+	_, _, err = splitDirectives("namespace a\n\n namespace b\n")
+	assert.Equal(t, &combinator.ParseError{Line: 3, Column: 2, Msg: "名前空間が重複して宣言されています。"}, err)
+}
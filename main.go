@@ -2,162 +2,305 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	flags "github.com/jessevdk/go-flags"
-	combinator "github.com/jiro4989/colc/combinator/v1"
+	"github.com/jiro4989/colc/basis"
+	combinator "github.com/jiro4989/colc/combinator/v2"
 	colcio "github.com/jiro4989/colc/io"
+	"github.com/jiro4989/colc/lambda"
 )
 
 // options オプション引数
 type options struct {
-	Version        func() `short:"v" long:"version" description:"バージョン情報"`
-	StepCount      int    `short:"s" long:"stepcount" description:"何ステップまで計算するか" default:"-1"`
-	OutFile        string `short:"o" long:"outfile" description:"出力ファイルパス"`
-	OutFileType    string `short:"t" long:"outfiletype" description:"出力ファイルの種類(なし|json)"`
-	Indent         string `short:"i" long:"indent" description:"outfiletypeが有効時に整形して出力する"`
-	CombinatorFile string `short:"c" long:"combinatorFile" description:"コンビネータ定義ファイルパス"`
-	PrintFlag      bool   `short:"p" long:"print" description:"計算過程を出力する"`
-	NoPrintHeader  bool   `short:"n" long:"noprintheader" description:"printフラグON時のヘッダ出力を消す"`
+	Version         func()        `short:"v" long:"version" description:"バージョン情報"`
+	StepCount       int           `short:"s" long:"stepcount" description:"何ステップまで計算するか" default:"-1"`
+	OutFile         string        `short:"o" long:"outfile" description:"出力ファイルパス"`
+	OutFileType     string        `short:"t" long:"outfiletype" description:"出力ファイルの種類(なし|json)"`
+	Indent          string        `short:"i" long:"indent" description:"outfiletypeが有効時に整形して出力する"`
+	CombinatorFiles []string      `short:"c" long:"combinatorFile" description:"コンビネータ定義ファイルパス。複数指定した場合は後に指定したファイルの定義で同じ名前の定義を上書きする。basis:skiのように指定すると組み込みの定義を読み込む"`
+	Basis           string        `long:"basis" description:"組み込みのコンビネータ定義。combinatorFileと一緒に指定した場合はファイルの定義を追加する" choice:"ski" choice:"sk" choice:"bckw" choice:"birds" choice:"arithmetic"`
+	Uses            []string      `long:"use" description:"名前空間の定義を「church.<zero>」のような修飾名に加えて修飾なしの名前でも使えるようにする"`
+	PrintFlag       bool          `short:"p" long:"print" description:"計算過程を出力する"`
+	NoPrintHeader   bool          `short:"n" long:"noprintheader" description:"printフラグON時のヘッダ出力を消す"`
+	Normal          bool          `long:"normal" description:"先頭が計算できなくなったら引数の中も計算し、正規形まで計算する(--strategy=normalと同じ)"`
+	Strategy        string        `long:"strategy" description:"簡約戦略" choice:"weakhead" choice:"normal" choice:"applicative" choice:"parallel" default:"weakhead"`
+	FullParen       bool          `long:"fullparen" description:"全ての関数適用を括弧で括って出力する"`
+	Spaced          bool          `long:"spaced" description:"関数適用の間を空白で区切って出力する"`
+	Syntax          string        `long:"syntax" description:"CLCodeの書式(compact:1文字ずつ区切る|spaced:空白か括弧で区切る|unlambda:Unlambdaの前置記法|iota:Iota|jot:Jot|bcl:BCL)" choice:"compact" choice:"spaced" choice:"unlambda" choice:"iota" choice:"jot" choice:"bcl" default:"compact"`
+	OutputSyntax    string        `long:"output-syntax" description:"出力の書式。指定がない場合はsyntaxと同じ書式で出力する" choice:"compact" choice:"spaced" choice:"unlambda" choice:"iota" choice:"jot" choice:"bcl"`
+	Lambda          bool          `long:"lambda" description:"入力をラムダ式として解析し、コンビネータに変換してから計算する"`
+	Abstraction     string        `long:"abstraction" description:"ラムダ式をコンビネータに変換するブラケット抽象のアルゴリズム" choice:"naive" choice:"turner" choice:"ski-eta" default:"naive"`
+	MaxSize         int           `long:"max-size" description:"項の大きさ(コンビネータの数)の上限。0の場合は上限なし"`
+	MaxDepth        int           `long:"max-depth" description:"項の深さの上限。0の場合は上限なし"`
+	Timeout         time.Duration `long:"timeout" description:"1行あたりの計算時間の上限(例: 500ms, 2s)。0の場合は上限なし"`
+	OutputLambda    bool          `long:"output-lambda" description:"計算結果をラムダ式に変換して出力する"`
+	Encoding        string        `long:"encoding" description:"#5、#true、#falseのようなリテラルの展開方法(church:チャーチ数|scott:スコット数)" choice:"church" choice:"scott" default:"church"`
+	Decode          string        `long:"decode" description:"計算結果を値として読み取って併記する(church:チャーチ数|bool:真偽値)。カンマ区切りで複数指定した場合は先に指定したものを優先する"`
+
+	EncodeCommand encodeCommand `command:"encode" description:"CLCodeを計算せずに符号に変換する"`
+	DecodeCommand decodeCommand `command:"decode" description:"符号をCLCodeに変換する"`
+	BasesCommand  struct{}      `command:"bases" description:"組み込みのコンビネータ定義の一覧を出力する"`
+	CheckDefs     struct{}      `command:"check-defs" description:"引数のコンビネータ定義ファイルの誤りを検査する"`
+
+	// command は指定されたサブコマンドの名前である。指定がない場合は空文字列である。
+	command string
+}
+
+// encodeCommand はencodeサブコマンドのオプション引数
+type encodeCommand struct {
+	BCL bool `long:"bcl" description:"BCL(二進コンビネータ論理)の符号に変換する"`
+}
+
+// decodeCommand はdecodeサブコマンドのオプション引数
+type decodeCommand struct {
+	BCL bool `long:"bcl" description:"BCL(二進コンビネータ論理)の符号を変換する"`
 }
 
 type OutValue struct {
-	Input   string   `json:"input"`
-	Process []string `json:"process"`
-	Result  string   `json:"result"`
+	Input   string            `json:"input"`
+	Process []string          `json:"process"`
+	Result  string            `json:"result"`
+	Outcome string            `json:"outcome,omitempty"`
+	Cycle   *combinator.Cycle `json:"cycle,omitempty"`
+	Decoded interface{}       `json:"decoded,omitempty"`
+	Bits    int               `json:"bits,omitempty"`
+	// FormatError は計算結果を出力の書式に変換できなかった理由である。
+	FormatError string `json:"formatError,omitempty"`
+	// LambdaError は計算結果をラムダ式に変換できなかった理由である。
+	LambdaError string `json:"lambdaError,omitempty"`
 }
 type OutValues []OutValue
 
+// decompileMaxSteps は計算結果をラムダ式に変換するときに計算するステップ数の上限である。
+const decompileMaxSteps = 10000
+
+// decodeMaxSteps は計算結果を値として読み取るときに計算するステップ数の上限である。
+const decodeMaxSteps = 10000
+
 // コンビネータ設定
 type Combinators []combinator.Combinator
 
 // combinators はコンビネータ定義
-var combinators = []combinator.Combinator{
-	combinator.Combinator{
-		Name:      "S",
-		ArgsCount: 3,
-		Format:    "{0}{2}({1}{2})",
-	},
-	combinator.Combinator{
-		Name:      "K",
-		ArgsCount: 2,
-		Format:    "{0}",
-	},
-	combinator.Combinator{
-		Name:      "I",
-		ArgsCount: 1,
-		Format:    "{0}",
-	},
-}
+var combinators = basis.SKI.Combinators
 
 func main() {
 	opts, args := parseOptions()
 
-	// コンビネータのファイルパス指定があれば上書き
-	if opts.CombinatorFile != "" {
-		var err error
-		combinators, err = ReadCombinator(opts.CombinatorFile)
-		if err != nil {
+	// 組み込みのコンビネータ定義の一覧を出力して終了する
+	if opts.command == "bases" {
+		if err := out(basesLines(), opts); err != nil {
 			panic(err)
 		}
+		return
+	}
+
+	// 引数のコンビネータ定義ファイルを検査して終了する
+	// 引数指定なしの場合はコンビネータのファイルパス指定を検査する
+	if opts.command == "check-defs" {
+		paths := args
+		if len(paths) < 1 {
+			paths = opts.CombinatorFiles
+		}
+		if len(paths) < 1 {
+			fmt.Fprintln(os.Stderr, "検査するコンビネータ定義ファイルを指定してください。")
+			os.Exit(1)
+		}
+		lines, ok := checkDefs(paths)
+		if err := out(lines, opts); err != nil {
+			panic(err)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 組み込みのコンビネータ定義かコンビネータのファイルパス指定があれば上書き
+	if opts.Basis != "" || 0 < len(opts.CombinatorFiles) || 0 < len(opts.Uses) {
+		var (
+			warnings []string
+			err      error
+		)
+		combinators, warnings, err = loadCombinators(opts)
+		if err != nil {
+			// 定義ファイルの誤りは内容を出力して異常終了する
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		warnings = append(warnings, combinator.PrefixWarnings(combinators)...)
+		for _, w := range warnings {
+			fmt.Fprintln(os.Stderr, "警告: "+w)
+		}
+	}
+
+	// ラムダ式を変換するのに必要なコンビネータが定義されていなければ異常終了する
+	if opts.Lambda {
+		if err := lambda.CheckBasis(lambda.Abstractions[opts.Abstraction], combinators); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 	}
 
-	failure := func(err error) {
-		panic(err)
+	// サブコマンドの変換方式の指定がなければ異常終了する
+	if err := opts.checkCommand(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// 値の読み取り方の指定が不正なら異常終了する
+	if _, err := opts.decoders(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// 構文エラーは標準エラー出力に出力し、全ての入力を処理したあとで異常終了する
+	var failed bool
+	failure := func(fn string) func(error) {
+		return func(err error) {
+			if pe, ok := err.(*combinator.ParseError); ok {
+				pe.File = fn
+				fmt.Fprintln(os.Stderr, pe)
+				failed = true
+				return
+			}
+			panic(err)
+		}
 	}
 
 	// 引数指定なしの場合は標準入力を処理
 	if len(args) < 1 {
 		r := os.Stdin
-		if err := calcOut(r, opts, out, failure); err != nil {
+		if err := calcOut(r, opts, out, failure("<stdin>")); err != nil {
 			panic(err)
 		}
-		return
 	}
 
 	// 引数指定ありの場合はファイル処理
 	for _, fn := range args {
 		err := colcio.WithOpen(fn, func(r io.Reader) error {
-			return calcOut(r, opts, out, failure)
+			return calcOut(r, opts, out, failure(fn))
 		})
 		if err != nil {
 			panic(err)
 		}
 	}
+
+	if failed {
+		os.Exit(1)
+	}
 }
 
 // calcOut はCLCodeを計算して、出力する。
 // 計算結果を引数の関数に渡し、失敗時は引数に渡した関数を適用する。
+// サブコマンドの指定がある場合は計算せずに変換する。
 func calcOut(r io.Reader, opts options, success func([]string, options) error, failure func(error)) error {
-	ss, err := calcCLCode(r, opts)
-	if err != nil {
+	calc := calcCLCode
+	switch opts.command {
+	case "encode":
+		calc = encodeCLCode
+	case "decode":
+		calc = decodeCLCode
+	}
+	ss, err := calc(r, opts)
+	if errs, ok := err.(ParseErrors); ok {
+		for _, e := range errs {
+			failure(e)
+		}
+	} else if err != nil {
 		failure(err)
 	}
 	return success(ss, opts)
 }
 
+// ParseErrors は入力の構文エラーの一覧である。
+type ParseErrors []*combinator.ParseError
+
+func (e ParseErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// appendParseError は構文エラーを入力全体での位置にしてerrsに追加する。
+// 構文エラー以外の場合はfalseを返す。
+func appendParseError(errs ParseErrors, err error, lineNo, indent int) (ParseErrors, bool) {
+	pe, ok := relocate(err, lineNo, indent).(*combinator.ParseError)
+	if !ok {
+		return errs, false
+	}
+	return append(errs, pe), true
+}
+
 // calcCLCode はCLCodeを計算し、スライスで返す。
 // OutFileTypeにJSON指定があった場合は、JSON文字列として返す
+// 構文エラーの行は飛ばして残りの行を計算し、計算結果と一緒にParseErrorsを返す。
 func calcCLCode(r io.Reader, opts options) ([]string, error) {
 	var (
-		res []string
-		ovs OutValues
-		sc  = bufio.NewScanner(r)
+		res    []string
+		ovs    OutValues
+		errs   ParseErrors
+		sc     = bufio.NewScanner(r)
+		lineNo int
 	)
 	for sc.Scan() {
+		lineNo++
 		line := sc.Text()
+		indent := len(line) - len(strings.TrimLeft(line, " "))
 		line = strings.Trim(line, " ")
 
-		var (
-			s       string
-			process []string
-		)
+		ov, err := calcLine(line, opts)
+		if err != nil {
+			var ok bool
+			if errs, ok = appendParseError(errs, err, lineNo, indent); !ok {
+				return nil, err
+			}
+			continue
+		}
+
 		// 出力フラグがある場合は、1ステップ毎に出力
 		if opts.PrintFlag {
 			// 出力無効化フラグがONなら非表示
 			if !opts.NoPrintHeader {
 				res = append(res, "=== "+line+" ===")
 			}
-			var (
-				bef = line
-				c   = opts.StepCount
-			)
-			for {
-				if c == 0 {
-					break
-				}
-				aft := combinator.CalcCLCode1Time(bef, combinators)
-				if bef == aft {
-					break
-				}
-				bef = aft
-
-				switch opts.OutFileType {
-				case "json":
-					process = append(process, bef)
-				}
-
-				res = append(res, bef)
-				c--
-			}
-			s = bef
-		} else {
-			s = combinator.CalcCLCode(line, combinators, opts.StepCount)
+			res = append(res, ov.Process...)
 		}
 
 		// JSON出力するときは最後にresを上書きするのでappendしないためにcontinue
 		switch opts.OutFileType {
 		case "json":
-			ov := OutValue{Input: line, Process: process, Result: s}
 			ovs = append(ovs, ov)
 			continue
 		}
 
+		// 値として読み取れた場合は値を併記する
+		// 計算を途中で打ち切った場合は理由を併記する
+		s := ov.Result
+		if ov.Decoded != nil {
+			s += fmt.Sprintf(" = %v", ov.Decoded)
+		}
+		if ov.Outcome != "" {
+			s += " # " + ov.Outcome
+		}
+		if ov.FormatError != "" {
+			s += " # " + ov.FormatError
+		}
+		if ov.LambdaError != "" {
+			s += " # " + ov.LambdaError
+		}
 		res = append(res, s)
 	}
 	if err := sc.Err(); err != nil {
@@ -183,9 +326,183 @@ func calcCLCode(r io.Reader, opts options) ([]string, error) {
 		res = []string{s}
 	}
 
+	if 0 < len(errs) {
+		return res, errs
+	}
+	return res, nil
+}
+
+// encodeCLCode はCLCodeを計算せずにサブコマンドで指定された符号に変換し、スライスで返す。
+// 符号に変換できない行は正規の形のCLCodeに理由を併記する。
+// 構文エラーの行は飛ばして残りの行を変換し、変換結果と一緒にParseErrorsを返す。
+func encodeCLCode(r io.Reader, opts options) ([]string, error) {
+	return convertLines(r, func(line string) (string, error) {
+		t, err := opts.parse(line)
+		if err != nil {
+			return "", err
+		}
+		s, err := combinator.FormatBCL(t)
+		if err != nil {
+			return combinator.Canonical(t) + " # " + err.Error(), nil
+		}
+		return s, nil
+	})
+}
+
+// decodeCLCode はサブコマンドで指定された符号をCLCodeに変換し、スライスで返す。
+// 構文エラーの行は飛ばして残りの行を変換し、変換結果と一緒にParseErrorsを返す。
+func decodeCLCode(r io.Reader, opts options) ([]string, error) {
+	return convertLines(r, func(line string) (string, error) {
+		t, err := combinator.ParseBCL(line, combinators)
+		if err != nil {
+			return "", err
+		}
+		return opts.format(t)
+	})
+}
+
+// convertLines は空行以外の行を1行ずつ変換し、スライスで返す。
+// 構文エラーの行は飛ばして残りの行を変換し、変換結果と一緒にParseErrorsを返す。
+func convertLines(r io.Reader, conv func(string) (string, error)) ([]string, error) {
+	var (
+		res    []string
+		errs   ParseErrors
+		sc     = bufio.NewScanner(r)
+		lineNo int
+	)
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		line = strings.Trim(line, " ")
+		if line == "" {
+			res = append(res, line)
+			continue
+		}
+		s, err := conv(line)
+		if err != nil {
+			var ok bool
+			if errs, ok = appendParseError(errs, err, lineNo, indent); !ok {
+				return nil, err
+			}
+			continue
+		}
+		res = append(res, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if 0 < len(errs) {
+		return res, errs
+	}
 	return res, nil
 }
 
+// relocate は行頭の空白を除いた1行の中での構文エラーの位置を、入力全体での位置にする。
+func relocate(err error, lineNo, indent int) error {
+	if pe, ok := err.(*combinator.ParseError); ok {
+		pe.Line += lineNo - 1
+		pe.Column += indent
+	}
+	return err
+}
+
+// calcLine は1行のCLCodeを計算し、計算結果を返す。
+// printフラグON時は計算過程に1ステップ毎の計算結果が入る。
+func calcLine(line string, opts options) (OutValue, error) {
+	ov := OutValue{Input: line, Result: line}
+	// 空行は計算せずにそのまま返す
+	if line == "" {
+		return ov, nil
+	}
+	t, err := opts.parse(line)
+	if err != nil {
+		return ov, err
+	}
+
+	r := combinator.Reducer{
+		Combinators: combinators,
+		Strategy:    opts.strategy(),
+		MaxSteps:    opts.StepCount,
+		MaxSize:     opts.MaxSize,
+		MaxDepth:    opts.MaxDepth,
+		DetectCycle: true,
+	}
+	if opts.PrintFlag {
+		r.OnStep = func(_ int, t combinator.Term) {
+			s, _ := opts.format(t)
+			ov.Process = append(ov.Process, s)
+		}
+	}
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	ret := r.ReduceContext(ctx, t)
+	ov.Result, err = opts.format(ret.Term)
+	ov.Outcome = ret.Message()
+	ov.Cycle = ret.Cycle
+	if err != nil {
+		ov.FormatError = err.Error()
+	}
+	// BCLの符号のビット数はJSONで出力する場合だけ数え、SとKだけの項でなければ出力しない
+	if opts.OutFileType == "json" {
+		if bcl, err := combinator.FormatBCL(ret.Term); err == nil {
+			ov.Bits = len(bcl)
+		}
+	}
+
+	// 値として読み取るのは計算不可能になった項だけで、読み取りの計算にも同じ上限を使う
+	ds, err := opts.decoders()
+	if err != nil {
+		return ov, err
+	}
+	nf := reachedNormalForm(ret, r)
+	if nf {
+		dr := r
+		dr.MaxSteps, dr.OnStep = decodeMaxSteps, nil
+		for _, d := range ds {
+			if v, ok := d.Decode(ctx, ret.Term, dr); ok {
+				ov.Decoded = v
+				break
+			}
+		}
+	}
+
+	// ラムダ式に変換するのは計算不可能になった項だけで、変換の計算にも同じ上限を使う
+	// ラムダ式に変換できない場合は計算結果をそのまま出力し、理由を併記する
+	if opts.OutputLambda {
+		if !nf {
+			ov.LambdaError = "計算不可能になっていない項はラムダ式に変換しません。"
+			return ov, nil
+		}
+		lr := r
+		lr.MaxSteps = decompileMaxSteps
+		lt, err := lambda.Decompile(ctx, ret.Term, lr)
+		if err != nil {
+			ov.LambdaError = err.Error()
+			return ov, nil
+		}
+		ov.Result = lt.String()
+	}
+	return ov, nil
+}
+
+// reachedNormalForm は計算結果が計算不可能になった項かを返す。
+// ステップ数の上限ちょうどで計算不可能になった場合も含む。
+func reachedNormalForm(ret combinator.Result, r combinator.Reducer) bool {
+	switch ret.Outcome {
+	case combinator.NormalForm:
+		return true
+	case combinator.StepLimit:
+		_, ok := r.Strategy.Step(ret.Term, r.Combinators)
+		return !ok
+	}
+	return false
+}
+
 // out は行配列をオプションに応じて出力する。
 // 出力先ファイルが指定されていなければ標準出力する。
 // 指定があればファイル出力する。
@@ -200,18 +517,347 @@ func out(lines []string, opts options) error {
 	return colcio.WriteFile(opts.OutFile, lines)
 }
 
+// definitionFile はコンビネータ定義ファイルの内容である。
+type definitionFile struct {
+	// Namespace はファイルの定義の名前空間である。
+	// 指定した場合は定義を「名前空間.名前」で参照する。
+	Namespace string `json:"namespace"`
+	// Imports はファイルの定義より先に読み込む定義ファイルのパスである。
+	Imports []string `json:"imports"`
+	// Uses は修飾なしの名前でも参照できるようにする名前空間である。
+	Uses        []string    `json:"uses"`
+	Combinators Combinators `json:"-"`
+
+	// path はファイルのパスである。
+	path string
+	// equations は等式で書かれた定義ファイルの、namespaceなどの行を除いた内容である。
+	// parseEquationsで解析するまでCombinatorsは空である。
+	equations *string
+}
+
 // ReadCombinator は指定パスのJSON設定ファイルを読み取る
+// paramsで引数名を宣言した場合はFormatで引数名を使え、argsCountを省略できる。
+// Formatに未定義の引数名がある場合はエラーを返す。
+// 拡張子が.defの場合は「S x y z = x z (y z)」のような等式で書かれた定義ファイルとして読み取る。
+// 等式の構文エラーの場合は*combinator.ParseErrorを返す。
+// importsで指定したファイルは読み込まず、名前空間で修飾せずに指定パスのファイルの定義だけを返す。
 func ReadCombinator(path string) (Combinators, error) {
-	b, err := ioutil.ReadFile(path)
+	file, err := readDefinitionFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if err := file.parseEquations(nil); err != nil {
+		return nil, err
+	}
+	return file.Combinators, nil
+}
+
+// readDefinitionFile は指定パスのコンビネータ定義ファイルを読み取る。
+// JSONの場合は定義の配列か、namespace、imports、uses、combinatorsをキーに持つオブジェクトを読み取る。
+// 等式の場合は「namespace 名前空間」、「import パス」、「use 名前空間」の行を読み取り、
+// 定義の等式はimportsを読み込んでからparseEquationsで解析する。
+func readDefinitionFile(path string) (definitionFile, error) {
+	var file definitionFile
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+
+	if filepath.Ext(path) == ".def" {
+		file, src, err := splitDirectives(string(b))
+		if err != nil {
+			if pe, ok := err.(*combinator.ParseError); ok {
+				pe.File = path
+			}
+			return file, err
+		}
+		file.path, file.equations = path, &src
+		return file, nil
+	}
+
+	// 配列でなければnamespaceやimportsを持つオブジェクトとして読み取る
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return file, fmt.Errorf("%s: %v", path, err)
+		}
+		var keys []string
+		for k := range raw {
+			if !fileKeys[k] {
+				keys = append(keys, k)
+			}
+		}
+		if 0 < len(keys) {
+			sort.Strings(keys)
+			return file, fmt.Errorf("%s: 不明なキー%sがあります。", path, strings.Join(keys, ", "))
+		}
+		if err := json.Unmarshal(b, &file); err != nil {
+			return file, fmt.Errorf("%s: %v", path, err)
+		}
+		b = raw["combinators"]
+		if b == nil {
+			b = []byte("[]")
+		}
+	}
 
 	var combs Combinators
 	if err := json.Unmarshal(b, &combs); err != nil {
-		return nil, err
+		return file, fmt.Errorf("%s: %v", path, err)
+	}
+
+	// 引数の数の指定がなければ引数名の数を引数の数とする
+	for i, c := range combs {
+		if c.ArgsCount == 0 {
+			combs[i].ArgsCount = len(c.Params)
+		}
+	}
+	errs := append(unknownKeys(b, combs), combinator.Validate(combs)...)
+	if 0 < len(errs) {
+		sort.SliceStable(errs, func(i, j int) bool {
+			return errs[i].Index < errs[j].Index
+		})
+		return file, &DefinitionErrors{Path: path, Errs: errs}
+	}
+	file.Combinators = combs
+	return file, nil
+}
+
+// parseEquations は等式で書かれた定義ファイルの定義を解析する。
+// 右辺はvisibleのコンビネータ名も含めて最長一致で区切って解析する。
+// 等式で書かれた定義ファイルでなければ何もしない。
+func (f *definitionFile) parseEquations(visible Combinators) error {
+	if f.equations == nil {
+		return nil
+	}
+	cs, err := combinator.ParseDefinitionsWith(*f.equations, visible)
+	if err != nil {
+		if pe, ok := err.(*combinator.ParseError); ok {
+			pe.File = f.path
+		}
+		return err
+	}
+	if errs := combinator.Validate(cs); 0 < len(errs) {
+		return &DefinitionErrors{Path: f.path, Errs: errs}
+	}
+	f.Combinators, f.equations = cs, nil
+	return nil
+}
+
+// fileKeys はJSONのコンビネータ定義ファイルのオブジェクトで使えるキーである。
+var fileKeys = map[string]bool{
+	"namespace":   true,
+	"imports":     true,
+	"uses":        true,
+	"combinators": true,
+}
+
+// definitionKeys はJSONのコンビネータ定義で使えるキーである。
+var definitionKeys = map[string]bool{
+	"name":      true,
+	"argsCount": true,
+	"format":    true,
+	"params":    true,
+}
+
+// unknownKeys はJSONのコンビネータ定義で使えないキーを誤りとして返す。
+func unknownKeys(b []byte, combs Combinators) []*combinator.DefinitionError {
+	var (
+		raws []map[string]json.RawMessage
+		errs []*combinator.DefinitionError
+	)
+	if err := json.Unmarshal(b, &raws); err != nil {
+		return nil
+	}
+	for i, raw := range raws {
+		var keys []string
+		for k := range raw {
+			if !definitionKeys[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			errs = append(errs, &combinator.DefinitionError{Index: i, Name: combs[i].Name, Msg: fmt.Sprintf("不明なキー%sがあります。", k)})
+		}
+	}
+	return errs
+}
+
+// DefinitionErrors はコンビネータ定義ファイルの誤りの一覧である。
+type DefinitionErrors struct {
+	// Path は定義ファイルのパスである。
+	Path string
+	// Errs はコンビネータ定義の誤りである。
+	Errs []*combinator.DefinitionError
+}
+
+func (e *DefinitionErrors) Error() string {
+	lines := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		lines[i] = e.Path + ": " + err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkDefs はコンビネータ定義ファイルをimportsも含めて検査し、結果を行配列で返す。
+// 誤りのあるファイルがあった場合はfalseを返す。
+func checkDefs(paths []string) ([]string, bool) {
+	var (
+		lines []string
+		ok    = true
+	)
+	for _, path := range paths {
+		l := newDefinitionLoader()
+		err := l.load(path)
+		if err == nil {
+			err = l.check()
+		}
+		if err != nil {
+			lines = append(lines, strings.Split(err.Error(), "\n")...)
+			ok = false
+			continue
+		}
+		lines = append(lines, path+": 誤りはありません。")
+	}
+	return lines, ok
+}
+
+// loadCombinators はオプションに指定されたコンビネータ定義と警告を返す。
+// 組み込みのコンビネータ定義、ファイルの定義の順に読み込み、
+// 同じ名前の定義は後から読み込んだ定義で置き換える。
+func loadCombinators(opts options) (Combinators, []string, error) {
+	l := newDefinitionLoader()
+	if opts.Basis != "" {
+		if err := l.load(basisPrefix + opts.Basis); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, path := range opts.CombinatorFiles {
+		if err := l.load(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, ns := range opts.Uses {
+		if err := l.use("--use", ns); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := l.check(); err != nil {
+		return nil, nil, err
+	}
+	return l.cs, l.warnings, nil
+}
+
+// basesLines は組み込みのコンビネータ定義の一覧を行配列で返す。
+func basesLines() []string {
+	var lines []string
+	for _, b := range basis.Bases {
+		lines = append(lines, fmt.Sprintf("%s: %s", b.Name, b.Description))
+		for _, c := range b.Combinators {
+			lines = append(lines, fmt.Sprintf("    %s(%d) = %s", c.Name, c.ArgsCount, c.Format))
+		}
 	}
-	return combs, nil
+	return lines
+}
+
+// strategy はオプションに応じた簡約戦略を返す。
+// 指定がない場合は先頭のコンビネータだけを計算する。
+func (opts options) strategy() combinator.Strategy {
+	if opts.Normal {
+		return combinator.Normal
+	}
+	if st, ok := combinator.Strategies[opts.Strategy]; ok {
+		return st
+	}
+	return combinator.WeakHead
+}
+
+// parse はオプションの書式に応じてCLCodeを解析する。
+// リテラルはオプションの展開方法で展開する。
+// ラムダ式の場合はブラケット抽象でコンビネータの項に変換する。
+func (opts options) parse(line string) (combinator.Term, error) {
+	if opts.Lambda {
+		t, err := lambda.Parse(line)
+		if err != nil {
+			return nil, err
+		}
+		return lambda.Compile(t, lambda.Abstractions[opts.Abstraction], combinators)
+	}
+	switch opts.Syntax {
+	case "unlambda":
+		return combinator.ParseUnlambda(line, combinators)
+	case "iota":
+		return combinator.ParseIota(line, combinators)
+	case "jot":
+		return combinator.ParseJot(line, combinators)
+	case "bcl":
+		return combinator.ParseBCL(line, combinators)
+	}
+	p := combinator.Parser{
+		Spaced:   opts.Syntax == "spaced",
+		Literals: combinator.Encodings[opts.Encoding],
+	}
+	return p.Parse(line, combinators)
+}
+
+// format はオプションに応じて項を文字列に変換する。
+// 出力の書式の指定がない場合は入力と同じ書式で出力する。
+// 指定の書式に変換できない場合は、正規の形の文字列とエラーを返す。
+func (opts options) format(t combinator.Term) (string, error) {
+	syntax := opts.OutputSyntax
+	if syntax == "" {
+		syntax = opts.Syntax
+	}
+	var format func(combinator.Term) (string, error)
+	switch syntax {
+	case "unlambda":
+		return combinator.FormatUnlambda(t), nil
+	case "iota":
+		format = combinator.FormatIota
+	case "jot":
+		format = combinator.FormatJot
+	case "bcl":
+		format = combinator.FormatBCL
+	}
+	if format != nil {
+		s, err := format(t)
+		if err != nil {
+			return combinator.Canonical(t), err
+		}
+		return s, nil
+	}
+	p := combinator.Printer{
+		FullParen: opts.FullParen,
+		Spaced:    opts.Spaced || syntax == "spaced",
+	}
+	return p.Print(t), nil
+}
+
+// checkCommand はサブコマンドに変換方式が指定されているかを確認する。
+func (opts options) checkCommand() error {
+	switch {
+	case opts.command == "encode" && !opts.EncodeCommand.BCL,
+		opts.command == "decode" && !opts.DecodeCommand.BCL:
+		return fmt.Errorf("%sコマンドの変換方式を指定してください。(--bcl)", opts.command)
+	}
+	return nil
+}
+
+// decoders はオプションに指定された値の読み取り方を指定順に返す。
+// 未知の読み取り方が指定された場合はエラーを返す。
+func (opts options) decoders() ([]combinator.Decoder, error) {
+	if opts.Decode == "" {
+		return nil, nil
+	}
+	var ds []combinator.Decoder
+	for _, nm := range strings.Split(opts.Decode, ",") {
+		d, ok := combinator.Decoders[strings.TrimSpace(nm)]
+		if !ok {
+			return nil, fmt.Errorf("不正な値の読み取り方です。decode=%s", nm)
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
 }
 
 // parseOptions はコマンドラインオプションを解析する。
@@ -223,10 +869,15 @@ func parseOptions() (options, []string) {
 		os.Exit(0)
 	}
 
-	args, err := flags.Parse(&opts)
+	p := flags.NewParser(&opts, flags.Default)
+	p.SubcommandsOptional = true
+	args, err := p.Parse()
 	if err != nil {
 		os.Exit(0)
 	}
+	if p.Active != nil {
+		opts.command = p.Active.Name
+	}
 
 	return opts, args
 }
@@ -7,7 +7,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jiro4989/colc/basis"
+	combinator "github.com/jiro4989/colc/combinator/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -111,7 +114,7 @@ func TestCalcOut(t *testing.T) {
 	}
 	o1 := options{StepCount: -1}
 	o2 := options{StepCount: 1}
-	o3 := options{StepCount: -1, CombinatorFile: "config/combinator.json"}
+	o3 := options{StepCount: -1, CombinatorFiles: []string{"config/combinator.json"}}
 	type TD struct {
 		r       io.Reader
 		opts    options
@@ -202,6 +205,37 @@ func TestCalcCLCode(t *testing.T) {
 	o7 := options{StepCount: -1, PrintFlag: true}
 	o8 := options{StepCount: 2, PrintFlag: true}
 	o9 := options{StepCount: 2, OutFileType: "json", PrintFlag: true}
+	o10 := options{StepCount: -1, Normal: true}
+	o11 := options{StepCount: 1, Strategy: "applicative"}
+	o12 := options{StepCount: -1, Strategy: "parallel", PrintFlag: true, NoPrintHeader: true}
+	o13 := options{StepCount: -1, Strategy: "applicative"}
+	o14 := options{StepCount: -1, Strategy: "applicative", OutFileType: "json"}
+	o15 := options{StepCount: -1, MaxSize: 30}
+	o16 := options{StepCount: -1, MaxDepth: 8, OutFileType: "json"}
+	o17 := options{StepCount: -1, Syntax: "spaced", PrintFlag: true}
+	o18 := options{StepCount: 1, FullParen: true}
+	o19 := options{StepCount: 1, Spaced: true}
+	o20 := options{StepCount: -1, Lambda: true, Strategy: "normal"}
+	o21 := options{StepCount: 0, Lambda: true}
+	o22 := options{StepCount: 0, Lambda: true, Abstraction: "ski-eta"}
+	o23 := options{StepCount: 5, OutputLambda: true}
+	o24 := options{StepCount: -1, Decode: "church,bool"}
+	o25 := options{StepCount: -1, Decode: "bool,church"}
+	o26 := options{StepCount: -1, Decode: "church,bool", OutFileType: "json"}
+	o27 := options{StepCount: -1, Encoding: "church", Strategy: "normal", Decode: "church,bool"}
+	o28 := options{StepCount: 0, Encoding: "scott"}
+	o29 := options{StepCount: -1, Syntax: "unlambda"}
+	o30 := options{StepCount: -1, OutputSyntax: "unlambda", PrintFlag: true, NoPrintHeader: true}
+	o31 := options{StepCount: -1, Syntax: "unlambda", OutputSyntax: "compact"}
+	o32 := options{StepCount: -1, Syntax: "iota", OutputSyntax: "compact", Strategy: "normal"}
+	o33 := options{StepCount: -1, Syntax: "jot", OutputSyntax: "compact", Strategy: "normal"}
+	o34 := options{StepCount: 0, OutputSyntax: "iota"}
+	o35 := options{StepCount: 0, OutputSyntax: "jot"}
+	o36 := options{StepCount: -1, Syntax: "bcl", OutputSyntax: "bcl", OutFileType: "json"}
+	o37 := options{StepCount: 1, Decode: "church,bool"}
+	o38 := options{StepCount: -1, MaxSize: 30, Decode: "church,bool"}
+	o39 := options{StepCount: -1, MaxSize: 30, OutputLambda: true, OutFileType: "json"}
+	o40 := options{StepCount: -1, OutputSyntax: "bcl", OutFileType: "json"}
 
 	tds := []TD{
 		TD{
@@ -231,7 +265,7 @@ func TestCalcCLCode(t *testing.T) {
 		TD{
 			r:    f("SSSSS"),
 			opts: o1,
-			s:    []string{"SS((SS)S)"},
+			s:    []string{"SS(SSS)"},
 			desc: "正常系:ネスト括弧の計算をする",
 		},
 		TD{
@@ -282,15 +316,224 @@ func TestCalcCLCode(t *testing.T) {
 		TD{
 			r:    f("SSSSSS"),
 			opts: o8,
-			s:    []string{"=== SSSSSS ===", "SS(SS)SS", "SS((SS)S)S", "SS((SS)S)S"},
+			s:    []string{"=== SSSSSS ===", "SS(SS)SS", "SS(SSS)S", "SS(SSS)S"},
 			desc: "正常系:計算回数指定",
 		},
 		TD{
 			r:    f("SSSSSS"),
 			opts: o9,
-			s:    []string{`[{"input":"SSSSSS","process":["SS(SS)SS","SS((SS)S)S"],"result":"SS((SS)S)S"}]`},
+			s:    []string{`[{"input":"SSSSSS","process":["SS(SS)SS","SS(SSS)S"],"result":"SS(SSS)S","bits":17}]`},
 			desc: "正常系:計算回数指定(json)",
 		},
+		TD{
+			r:    f("K(Ix)", "SKIx"),
+			opts: o10,
+			s:    []string{"Kx", "x"},
+			desc: "正常系:引数の中も計算する",
+		},
+		TD{
+			r:    f("x(SII(SII))"),
+			opts: o10,
+			s:    []string{"x(I(SII)(I(SII))) # cycle of period 1 ignoring identity applications detected at step 1"},
+			desc: "正常系:引数の中でIの適用が増えていく循環を検出したら計算を終了する",
+		},
+		TD{
+			r:    f("Sxyz", "", "SKIx"),
+			opts: o1,
+			s:    []string{"xz(yz)", "", "x"},
+			desc: "正常系:空行はそのまま出力する",
+		},
+		TD{
+			r:    f("KI(SII(SII))"),
+			opts: o11,
+			s:    []string{"KI(I(SII)(I(SII)))"},
+			desc: "正常系:簡約戦略の指定",
+		},
+		TD{
+			r:    f("x(Ia)(Ib)"),
+			opts: o12,
+			s:    []string{"xab", "xab"},
+			desc: "正常系:最外のコンビネータを同時に計算する",
+		},
+		TD{
+			r:    f("SII(SII)", "Sxyz"),
+			opts: o13,
+			s:    []string{"SII(SII) # cycle of period 3 detected at step 3", "xz(yz)"},
+			desc: "正常系:循環を検出したら計算を終了する",
+		},
+		TD{
+			r:    f("SII(SII)", "Sxyz"),
+			opts: o1,
+			s:    []string{"I(SII)(I(SII)) # cycle of period 1 ignoring identity applications detected at step 1", "xz(yz)"},
+			desc: "正常系:オプションなしでも引数にIの適用が増えていく循環を検出したら計算を終了する",
+		},
+		TD{
+			r:    f("SII(SII)"),
+			opts: o14,
+			s:    []string{`[{"input":"SII(SII)","process":null,"result":"SII(SII)","outcome":"cycle of period 3 detected at step 3","cycle":{"period":3,"step":3},"bits":41}]`},
+			desc: "正常系:循環を検出したら計算を終了する(json)",
+		},
+		TD{
+			r:    f("S(SKK)(SKK)(S(SKK)(SKK))", "Sxyz"),
+			opts: o15,
+			s:    []string{"K(SKK(S(SKK)(SKK)))(K(SKK(S(SKK)(SKK))))(SKK(SKK(S(SKK)(SKK)))) # size limit exceeded at step 5", "xz(yz)"},
+			desc: "正常系:項の大きさが上限を超えたら計算を終了する",
+		},
+		TD{
+			r:    f("S(SKK)(SKK)(S(SKK)(SKK))"),
+			opts: o16,
+			s:    []string{`[{"input":"S(SKK)(SKK)(S(SKK)(SKK))","process":null,"result":"K(SKK(S(SKK)(SKK)))(K(SKK(S(SKK)(SKK))))(SKK(SKK(S(SKK)(SKK))))","outcome":"depth limit exceeded at step 5","bits":104}]`},
+			desc: "正常系:項の深さが上限を超えたら計算を終了する(json)",
+		},
+		TD{
+			r:    f("S foo bar baz", "K (I foo) bar"),
+			opts: o17,
+			s:    []string{"=== S foo bar baz ===", "foo baz (bar baz)", "foo baz (bar baz)", "=== K (I foo) bar ===", "I foo", "foo", "foo"},
+			desc: "正常系:空白区切りの識別子",
+		},
+		TD{
+			r:    f("SSSSS"),
+			opts: o18,
+			s:    []string{"(((SS)(SS))S)"},
+			desc: "正常系:全ての関数適用を括弧で括る",
+		},
+		TD{
+			r:    f("SSSSS"),
+			opts: o19,
+			s:    []string{"S S (S S) S"},
+			desc: "正常系:空白で区切る",
+		},
+		TD{
+			r:    f(`(\x.\y.y x) a b`, `(λf.λx.f (f x)) g z`),
+			opts: o20,
+			s:    []string{"ba", "g(gz)"},
+			desc: "正常系:ラムダ式を変換して計算する",
+		},
+		TD{
+			r:    f(`\x.\y.x`),
+			opts: o21,
+			s:    []string{"S(KK)I"},
+			desc: "正常系:ラムダ式を変換するだけ",
+		},
+		TD{
+			r:    f(`\x.\y.y x`),
+			opts: o22,
+			s:    []string{"S(K(SI))K"},
+			desc: "正常系:ブラケット抽象のアルゴリズムを指定する",
+		},
+		TD{
+			r:    f("S(K(SI))K", "SKK", "Kab", "SII(SII)"),
+			opts: o23,
+			s: []string{
+				`\x.\y.y x`,
+				`\x.x`,
+				`a`,
+				"I(SII)(I(SII)) # cycle of period 1 ignoring identity applications detected at step 1 # 計算不可能になっていない項はラムダ式に変換しません。",
+			},
+			desc: "正常系:計算結果をラムダ式に変換する",
+		},
+		TD{
+			r:    f("S(SKK)(SKK)(S(SKK)(SKK))", "K(S(SKK)(SKK)(S(SKK)(SKK)))"),
+			opts: o39,
+			s:    []string{`[{"input":"S(SKK)(SKK)(S(SKK)(SKK))","process":null,"result":"K(SKK(S(SKK)(SKK)))(K(SKK(S(SKK)(SKK))))(SKK(SKK(S(SKK)(SKK))))","outcome":"size limit exceeded at step 5","bits":104,"lambdaError":"計算不可能になっていない項はラムダ式に変換しません。"},{"input":"K(S(SKK)(SKK)(S(SKK)(SKK)))","process":null,"result":"K(S(SKK)(SKK)(S(SKK)(SKK)))","bits":44,"lambdaError":"ラムダ式に変換できませんでした。size limit exceeded at step 6"}]`},
+			desc: "正常系:ラムダ式への変換にも項の大きさの上限を使い、変換できない理由は別の項目に出力する(json)",
+		},
+		TD{
+			r:    f("S(S(KS)K)I", "K", "KI", "Sx"),
+			opts: o24,
+			s:    []string{"S(S(KS)K)I = 2", "K = true", "KI = 0", "Sx"},
+			desc: "正常系:計算結果を値として読み取る",
+		},
+		TD{
+			r:    f("KI"),
+			opts: o25,
+			s:    []string{"KI = false"},
+			desc: "正常系:先に指定した読み取り方を優先する",
+		},
+		TD{
+			r:    f("K", "KI", "Sx"),
+			opts: o26,
+			s:    []string{`[{"input":"K","process":null,"result":"K","decoded":true,"bits":2},{"input":"KI","process":null,"result":"KI","decoded":0,"bits":11},{"input":"Sx","process":null,"result":"Sx"}]`},
+			desc: "正常系:計算結果を値として読み取る(json)",
+		},
+		TD{
+			r:    f("I(S(S(KS)K)I)", "I(I(S(S(KS)K)I))"),
+			opts: o37,
+			s:    []string{"S(S(KS)K)I = 2", "I(S(S(KS)K)I)"},
+			desc: "正常系:ステップ数の上限までに計算不可能にならなければ値として読み取らない",
+		},
+		TD{
+			r:    f("S(SKK)(SKK)(S(SKK)(SKK))"),
+			opts: o38,
+			s:    []string{"K(SKK(S(SKK)(SKK)))(K(SKK(S(SKK)(SKK))))(SKK(SKK(S(SKK)(SKK)))) # size limit exceeded at step 5"},
+			desc: "正常系:項の大きさが上限を超えたら値として読み取らない",
+		},
+		TD{
+			r:    f("#3", "#truexy", "#1#false"),
+			opts: o27,
+			s:    []string{"SB(SB(SB(KI))) = 3", "x", "B(KI)I = 0"},
+			desc: "正常系:リテラルを展開する",
+		},
+		TD{
+			r:    f("#1", "#false"),
+			opts: o28,
+			s:    []string{"K(CIK)", "KI"},
+			desc: "正常系:リテラルの展開方法を指定する",
+		},
+		TD{
+			r:    f("```skkx", "```sxyz # コメント"),
+			opts: o29,
+			s:    []string{"x", "``xz`yz"},
+			desc: "正常系:Unlambdaの書式で入出力する",
+		},
+		TD{
+			r:    f("SKKx"),
+			opts: o30,
+			s:    []string{"``kx`kx", "x", "x"},
+			desc: "正常系:Unlambdaの書式で出力する",
+		},
+		TD{
+			r:    f("```sxyz"),
+			opts: o31,
+			s:    []string{"xz(yz)"},
+			desc: "正常系:Unlambdaの書式で入力して別の書式で出力する",
+		},
+		TD{
+			r:    f("*ii", "*i*i*ii"),
+			opts: o32,
+			s:    []string{"SK(KK)", "K"},
+			desc: "正常系:Iotaの書式で入力する",
+		},
+		TD{
+			r:    f("11100", "1111100011100"),
+			opts: o33,
+			s:    []string{"K", "SK"},
+			desc: "正常系:Jotの書式で入力する",
+		},
+		TD{
+			r:    f("SKK", "Sx"),
+			opts: o34,
+			s:    []string{"***i*i*i*ii*i*i*ii*i*i*ii", "Sx # Iotaの書式に変換できないコンビネータです。名前=x"},
+			desc: "正常系:Iotaの書式で出力する",
+		},
+		TD{
+			r:    f("SKK"),
+			opts: o35,
+			s:    []string{"11111110001110011100"},
+			desc: "正常系:Jotの書式で出力する",
+		},
+		TD{
+			r:    f("11000001"),
+			opts: o36,
+			s:    []string{`[{"input":"11000001","process":null,"result":"00","bits":2}]`},
+			desc: "正常系:BCLの書式で入出力し、項の大きさをビット数で出力する",
+		},
+		TD{
+			r:    f("KSx", "Kxy"),
+			opts: o40,
+			s:    []string{`[{"input":"KSx","process":null,"result":"01","bits":2},{"input":"Kxy","process":null,"result":"x","formatError":"BCLの書式に変換できないコンビネータです。名前=x"}]`},
+			desc: "正常系:SとKだけの項でなければビット数を出力せず、書式に変換できない理由は別の項目に出力する(json)",
+		},
 	}
 	for _, v := range tds {
 		r, opts, expect, desc := v.r, v.opts, v.s, v.desc
@@ -299,3 +542,210 @@ func TestCalcCLCode(t *testing.T) {
 		assert.NoError(t, err, desc)
 	}
 }
+
+func TestCalcCLCodeTimeout(t *testing.T) {
+	// 計算が終わらない項でも、時間の上限で計算を打ち切る
+	r := bytes.NewBufferString("S(SKK)(SKK)(S(SKK)(SKK))\nSxyz")
+	opts := options{StepCount: -1, Timeout: 10 * time.Millisecond}
+	actual, err := calcCLCode(r, opts)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 2)
+	assert.Contains(t, actual[0], " # timed out at step ")
+	assert.Equal(t, "xz(yz)", actual[1], "上限は1行毎に適用する")
+}
+
+func TestEncodeDecodeCLCode(t *testing.T) {
+	f := func(ss ...string) io.Reader {
+		return bytes.NewBufferString(strings.Join(ss, "\n"))
+	}
+	type TD struct {
+		r      io.Reader
+		opts   options
+		expect []string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			r:      f("SKK", "", "S(KK)", "Sx"),
+			opts:   options{command: "encode", EncodeCommand: encodeCommand{BCL: true}},
+			expect: []string{"11010000", "", "10110000", "Sx # BCLの書式に変換できないコンビネータです。名前=x"},
+			desc:   "正常系:BCLに変換する",
+		},
+		TD{
+			r:      f("``skk"),
+			opts:   options{command: "encode", EncodeCommand: encodeCommand{BCL: true}, Syntax: "unlambda"},
+			expect: []string{"11010000"},
+			desc:   "正常系:入力の書式を指定してBCLに変換する",
+		},
+		TD{
+			r:      f("11010000", "  1 01 100 00"),
+			opts:   options{command: "decode", DecodeCommand: decodeCommand{BCL: true}},
+			expect: []string{"SKK", "S(KK)"},
+			desc:   "正常系:BCLを変換する",
+		},
+		TD{
+			r:      f("11010000"),
+			opts:   options{command: "decode", DecodeCommand: decodeCommand{BCL: true}, OutputSyntax: "unlambda"},
+			expect: []string{"``skk"},
+			desc:   "正常系:出力の書式を指定してBCLを変換する",
+		},
+	}
+	for _, td := range tds {
+		var actual []string
+		err := calcOut(td.r, td.opts, func(ss []string, opts options) error {
+			actual = ss
+			return nil
+		}, func(err error) {
+			assert.NoError(t, err, td.desc)
+		})
+		assert.NoError(t, err, td.desc)
+		assert.Equal(t, td.expect, actual, td.desc)
+	}
+
+	actual, err := decodeCLCode(f("00", "  12", "01"), options{})
+	assert.Equal(t, ParseErrors{&combinator.ParseError{Line: 2, Column: 4, Msg: "BCLの書式で使えない文字です。文字=2"}}, err, "行と桁は入力全体での位置")
+	assert.Equal(t, []string{"K", "S"}, actual, "構文エラーの行を飛ばして変換する")
+
+	err = options{command: "encode"}.checkCommand()
+	assert.EqualError(t, err, "encodeコマンドの変換方式を指定してください。(--bcl)")
+}
+
+func TestCalcCLCodeParseError(t *testing.T) {
+	r := bytes.NewBufferString("Sxyz\n  S(Kx\nSKIx")
+	actual, err := calcCLCode(r, options{StepCount: -1})
+	assert.Equal(t, ParseErrors{&combinator.ParseError{Line: 2, Column: 4, Msg: "対応する閉じ括弧がありません。"}}, err, "行と桁は入力全体での位置")
+	assert.Equal(t, []string{"xz(yz)", "x"}, actual, "構文エラーの行を飛ばして計算する")
+
+	var (
+		failed []error
+		out    []string
+	)
+	err = calcOut(bytes.NewBufferString("Sx)y\nKxy\n(S"), options{StepCount: -1}, func(ss []string, opts options) error {
+		out = ss
+		return nil
+	}, func(err error) {
+		failed = append(failed, err)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x"}, out, "構文エラーがあっても計算結果を出力する")
+	assert.Equal(t, []error{
+		&combinator.ParseError{Line: 1, Column: 3, Msg: "対応する開き括弧がありません。"},
+		&combinator.ParseError{Line: 3, Column: 1, Msg: "対応する閉じ括弧がありません。"},
+	}, failed, "全ての構文エラーを渡す")
+}
+
+func TestLoadCombinators(t *testing.T) {
+	file, err := ReadCombinator("config/combinator.json")
+	assert.NoError(t, err)
+	assert.Equal(t, Combinators(basis.Arithmetic.Combinators), file, "組み込みの定義とファイルの定義が一致する")
+
+	type TD struct {
+		opts   options
+		expect []string
+		desc   string
+	}
+	tds := []TD{
+		TD{
+			opts:   options{Basis: "bckw"},
+			expect: []string{"B", "C", "K", "W"},
+			desc:   "組み込みの定義",
+		},
+		TD{
+			opts:   options{CombinatorFiles: []string{"config/combinator.json"}},
+			expect: []string{"S", "K", "I", "B", "C", "D", "<0>", "<1>", "<zero>", "<suc>", "<one>", "<p1_1>", "<p2_1>", "<p2_2>", "<p3_1>", "<p3_2>", "<p3_3>", "Q", "R", "<true>", "<false>"},
+			desc:   "ファイルの定義",
+		},
+		TD{
+			opts:   options{Basis: "bckw", CombinatorFiles: []string{"config/combinator.json"}},
+			expect: []string{"B", "C", "K", "W", "S", "I", "D", "<0>", "<1>", "<zero>", "<suc>", "<one>", "<p1_1>", "<p2_1>", "<p2_2>", "<p3_1>", "<p3_2>", "<p3_3>", "Q", "R", "<true>", "<false>"},
+			desc:   "組み込みの定義にファイルの定義を追加する",
+		},
+	}
+	for _, td := range tds {
+		cs, _, err := loadCombinators(td.opts)
+		assert.NoError(t, err, td.desc)
+		var names []string
+		for _, c := range cs {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, td.expect, names, td.desc)
+	}
+
+	_, warnings, err := loadCombinators(options{Basis: "bckw", CombinatorFiles: []string{"basis:ski", "testdata/in/imports/override.def"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testdata/in/imports/numbers.jsonのコンビネータ<zero>の定義をtestdata/in/imports/override.defの定義で上書きします。"}, warnings, "複数のファイルを重ねる")
+
+	cs, _, err := loadCombinators(options{CombinatorFiles: []string{"testdata/in/namespace/church.json", "testdata/in/namespace/scott.def"}, Uses: []string{"scott"}})
+	assert.NoError(t, err)
+	for in, expect := range map[string]string{"church.<one>fx": "fx", "scott.<one>ab": "bK", "<one>ab": "bK"} {
+		actual, err := combinator.CalcCLCode(in, cs, -1, combinator.Normal)
+		assert.NoError(t, err, in)
+		assert.Equal(t, expect, actual, "入力で修飾名と修飾なしの名前を使う", in)
+	}
+
+	_, _, err = loadCombinators(options{Basis: "foo"})
+	assert.EqualError(t, err, "不正な組み込みのコンビネータ定義です。basis=foo")
+}
+
+func TestReadCombinatorDefinitions(t *testing.T) {
+	fromDef, err := ReadCombinator("config/combinator.def")
+	assert.NoError(t, err)
+	fromJSON, err := ReadCombinator("config/combinator.json")
+	assert.NoError(t, err)
+	assert.Equal(t, fromJSON, fromDef, "等式の定義ファイルとJSONの定義ファイルが一致する")
+
+	_, err = ReadCombinator("testdata/in/broken_combinator.def")
+	assert.Equal(t, &combinator.ParseError{File: "testdata/in/broken_combinator.def", Line: 3, Column: 15, Msg: "対応する閉じ括弧がありません。"}, err)
+}
+
+func TestReadCombinatorParams(t *testing.T) {
+	cs, err := ReadCombinator("testdata/in/named_combinator.json")
+	assert.NoError(t, err)
+	assert.Equal(t, Combinators{
+		combinator.Combinator{Name: "S", ArgsCount: 3, Format: "{x}{z}({y}{z})", Params: []string{"x", "y", "z"}},
+		combinator.Combinator{Name: "K", ArgsCount: 2, Format: "{x}", Params: []string{"x", "y"}},
+		combinator.Combinator{Name: "I", ArgsCount: 1, Format: "{0}"},
+		combinator.Combinator{Name: "R", ArgsCount: 3, Format: "{y}{z}{x}", Params: []string{"x", "y", "z"}},
+	}, cs, "引数の数の指定がなければ引数名の数を引数の数とする")
+	actual, err := combinator.CalcCLCode("SKIx", cs, -1, combinator.WeakHead)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", actual)
+	actual, err = combinator.CalcCLCode("Rabc", cs, -1, combinator.WeakHead)
+	assert.NoError(t, err)
+	assert.Equal(t, "bca", actual)
+
+	_, err = ReadCombinator("testdata/in/unknown_param_combinator.json")
+	assert.EqualError(t, err, "testdata/in/unknown_param_combinator.json: 2番目のコンビネータK: Formatの4文字目: 引数名wは定義されていません。")
+}
+
+func TestCheckDefs(t *testing.T) {
+	type TD struct {
+		paths  []string
+		expect []string
+		ok     bool
+		desc   string
+	}
+	tds := []TD{
+		TD{paths: []string{"config/combinator.json", "config/combinator.def"}, expect: []string{"config/combinator.json: 誤りはありません。", "config/combinator.def: 誤りはありません。"}, ok: true, desc: "誤りなし"},
+		TD{paths: []string{"testdata/in/basic_combinator.json", "testdata/in/invalid_combinator.json"}, expect: []string{
+			"testdata/in/basic_combinator.json: 誤りはありません。",
+			"testdata/in/invalid_combinator.json: 2番目のコンビネータ: 不明なキーcombinatorNameがあります。",
+			"testdata/in/invalid_combinator.json: 2番目のコンビネータ: 名前が空です。",
+			"testdata/in/invalid_combinator.json: 3番目のコンビネータI(: 名前に使えない文字「(」が含まれています。",
+			"testdata/in/invalid_combinator.json: 4番目のコンビネータS: 名前が1番目のコンビネータと重複しています。",
+			"testdata/in/invalid_combinator.json: 4番目のコンビネータS: Formatの引数の番号3が引数の数2を超えています。",
+		}, ok: false, desc: "全ての誤りを出力する"},
+		TD{paths: []string{"testdata/in/recursive_combinator.def"}, expect: []string{"testdata/in/recursive_combinator.def: 5番目のコンビネータD: 引数のないコンビネータの参照が循環しています。循環=D -> E -> D"}, ok: false, desc: "引数のないコンビネータの参照の循環"},
+		TD{paths: []string{"testdata/in/broken_combinator.def"}, expect: []string{"testdata/in/broken_combinator.def:3:15: 対応する閉じ括弧がありません。"}, ok: false, desc: "等式の定義の解析エラー"},
+	}
+	for _, td := range tds {
+		actual, ok := checkDefs(td.paths)
+		assert.Equal(t, td.expect, actual, td.desc)
+		assert.Equal(t, td.ok, ok, td.desc)
+	}
+}
+
+func TestBasesLines(t *testing.T) {
+	lines := basesLines()
+	assert.Equal(t, []string{"ski: S、K、I", "    S(3) = {0}{2}({1}{2})", "    K(2) = {0}", "    I(1) = {0}"}, lines[:4])
+}